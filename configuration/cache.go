@@ -0,0 +1,360 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package configuration
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/kvtree"
+	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/retry"
+
+	"github.com/edgexfoundry/go-mod-messaging/v3/messaging"
+)
+
+// cachingClient caches the configuration loaded from a Client, persisted to a snapshot file, so that reads are served
+// from the last known good configuration while the Configuration service is unavailable
+type cachingClient struct {
+	Client
+	snapshotPath string
+	lock         sync.Mutex
+	// pairs is the cached configuration, keyed relative to the service's base path.
+	// It is nil until the configuration has been loaded, either from the Client or from the snapshot file.
+	pairs map[string]string
+	stale bool
+	// snapshotErr is the error persisting the cache to the snapshot file has last failed with
+	snapshotErr error
+	watcher     *mapWatcher
+}
+
+// NewCachingClient creates a Client which caches the configuration of client and persists it to the snapshot file at
+// snapshotPath. The cache is refreshed whenever the full configuration is read successfully, then by the values read
+// successfully and on watch updates. Should a read fail as the Configuration service is unavailable, i.e. unreachable
+// at startup, it is served from the cache instead, which is loaded from the snapshot file if needed, and flagged as
+// stale. Any other error, i.e. configuration not found, is returned as is. A failure to persist the cache doesn't fail
+// the read, it is reported by LastSnapshotError instead.
+// All writes are passed on to client unchanged.
+func NewCachingClient(client Client, snapshotPath string) (CachingClient, error) {
+	if snapshotPath == "" {
+		return nil, errors.New("the snapshot path of a caching configuration client is required")
+	}
+
+	return &cachingClient{
+		Client:       client,
+		snapshotPath: snapshotPath,
+		watcher:      newMapWatcher(client),
+	}, nil
+}
+
+// Stale reports whether the last read has been served from the cache rather than the Configuration service
+func (client *cachingClient) Stale() bool {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	return client.stale
+}
+
+// LastSnapshotError returns the error the cache has last failed to be persisted to the snapshot file with, or nil if
+// it has been persisted since
+func (client *cachingClient) LastSnapshotError() error {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	return client.snapshotErr
+}
+
+// HasConfiguration checks to see if the Configuration service, or the cache if it is unavailable, contains the
+// service's configuration.
+func (client *cachingClient) HasConfiguration() (bool, error) {
+	exists, err := client.Client.HasConfiguration()
+	if err == nil {
+		client.setStale(false)
+		return exists, nil
+	}
+
+	pairs, cacheErr := client.cached(err)
+	if cacheErr != nil {
+		return false, cacheErr
+	}
+
+	return len(pairs) > 0, nil
+}
+
+// HasSubConfiguration checks to see if the Configuration service, or the cache if it is unavailable, contains the
+// service's sub configuration.
+func (client *cachingClient) HasSubConfiguration(name string) (bool, error) {
+	exists, err := client.Client.HasSubConfiguration(name)
+	if err == nil {
+		client.setStale(false)
+		return exists, nil
+	}
+
+	pairs, cacheErr := client.cached(err)
+	if cacheErr != nil {
+		return false, cacheErr
+	}
+
+	return len(kvtree.Subtree(trimKey(name), pairs)) > 0, nil
+}
+
+// GetConfiguration gets the full configuration from the Configuration service into the target configuration struct
+// and caches it. The cached configuration is used if the Configuration service is unavailable.
+// Returns the configuration in the target struct as interface{}, which caller must cast
+func (client *cachingClient) GetConfiguration(configStruct interface{}) (interface{}, error) {
+	var pairs map[string]string
+
+	raw, err := client.Client.GetConfiguration(&map[string]any{})
+	if err == nil {
+		values, err := toMap(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		pairs = kvtree.Flatten("", values)
+		client.refresh("", pairs)
+	} else {
+		pairs, err = client.cached(err)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := kvtree.Decode("", pairs, configStruct); err != nil {
+		return nil, err
+	}
+
+	return configStruct, nil
+}
+
+// ConfigurationValueExists checks if a configuration value exists in the Configuration service, or in the cache if
+// it is unavailable
+func (client *cachingClient) ConfigurationValueExists(name string) (bool, error) {
+	exists, err := client.Client.ConfigurationValueExists(name)
+	if err == nil {
+		client.setStale(false)
+		return exists, nil
+	}
+
+	pairs, cacheErr := client.cached(err)
+	if cacheErr != nil {
+		return false, cacheErr
+	}
+
+	_, exists = pairs[trimKey(name)]
+	return exists, nil
+}
+
+// GetConfigurationValue gets a specific configuration value from the Configuration service, refreshing the cache with
+// it, or from the cache if it is unavailable
+func (client *cachingClient) GetConfigurationValue(name string) ([]byte, error) {
+	value, err := client.Client.GetConfigurationValue(name)
+	if err == nil {
+		client.refreshValue(trimKey(name), value)
+		return value, nil
+	}
+
+	pairs, cacheErr := client.cached(err)
+	if cacheErr != nil {
+		return nil, cacheErr
+	}
+
+	cachedValue, exists := pairs[trimKey(name)]
+	if !exists {
+		return nil, nil
+	}
+
+	return []byte(cachedValue), nil
+}
+
+// WatchForChanges sets up a watch for the target key on the wrapped Client and sends back its updates on the update
+// channel, which also refresh the cache.
+// Sends the configuration in the target struct as interface{} on updateChannel, which caller must cast
+func (client *cachingClient) WatchForChanges(updateChannel chan<- interface{}, errorChannel chan<- error, configuration interface{}, waitKey string, msgClient messaging.MessageClient) {
+	client.watcher.watch(updateChannel, errorChannel, configuration, waitKey, msgClient, client.decode)
+}
+
+// decode refreshes the cache with the watched configuration map located at prefix and decodes it into target
+func (client *cachingClient) decode(prefix string, raw interface{}, target interface{}) error {
+	values, err := toMap(raw)
+	if err != nil {
+		return err
+	}
+
+	pairs := kvtree.Flatten(prefix, values)
+	client.refresh(prefix, pairs)
+
+	return kvtree.Decode(prefix, pairs, target)
+}
+
+// StopWatching causes all WatchForChanges processing of the wrapped Client to stop and waits until they have stopped.
+func (client *cachingClient) StopWatching() {
+	client.watcher.stop()
+}
+
+// Close stops all watches and closes the wrapped Client, which must not be used afterward
+func (client *cachingClient) Close() error {
+	client.watcher.stop()
+	return closeClient(client.Client)
+}
+
+func (client *cachingClient) setStale(stale bool) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	client.stale = stale
+}
+
+// cached returns the cached configuration after a read from the Configuration service has failed with readErr as it
+// is unavailable, and flags it as stale. The configuration is loaded from the snapshot file if it hasn't been cached
+// yet. readErr is returned as is if the Configuration service is available.
+func (client *cachingClient) cached(readErr error) (map[string]string, error) {
+	if !client.unavailable(readErr) {
+		return nil, readErr
+	}
+
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	if client.pairs == nil {
+		pairs, err := client.readSnapshot()
+		if err != nil {
+			return nil, errors.Join(readErr, err)
+		}
+		client.pairs = pairs
+	}
+
+	client.stale = true
+	return client.pairs, nil
+}
+
+// refresh replaces the cached values under prefix by pairs and persists the cache to the snapshot file.
+// The cache is only refreshed by a watch update, i.e. when prefix isn't empty, once it holds the full configuration.
+func (client *cachingClient) refresh(prefix string, pairs map[string]string) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	client.stale = false
+
+	if prefix != "" && client.pairs == nil {
+		return
+	}
+
+	// the cached map is replaced rather than updated, since it might still be decoded by a read
+	refreshed := make(map[string]string, len(client.pairs)+len(pairs))
+	for key, value := range client.pairs {
+		if !kvtree.HasPrefix(key, prefix) {
+			refreshed[key] = value
+		}
+	}
+	for key, value := range pairs {
+		refreshed[key] = value
+	}
+	client.pairs = refreshed
+	client.snapshotErr = client.writeSnapshot()
+}
+
+// refreshValue replaces the cached value of key by the value read from the Configuration service, or removes it if
+// value is nil, i.e. not found, and persists the cache to the snapshot file if it has changed. Like watch updates,
+// values only refresh the cache once it holds the full configuration.
+func (client *cachingClient) refreshValue(key string, value []byte) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	client.stale = false
+
+	if client.pairs == nil {
+		return
+	}
+
+	cachedValue, cached := client.pairs[key]
+	if (value == nil && !cached) || (value != nil && cached && cachedValue == string(value)) {
+		return
+	}
+
+	// the cached map is replaced rather than updated, since it might still be decoded by a read
+	refreshed := make(map[string]string, len(client.pairs)+1)
+	for existing, existingValue := range client.pairs {
+		// a value replaces the whole directory at its key, and the value of any of its parents
+		if existing != key && (value == nil || (!kvtree.HasPrefix(existing, key) && !kvtree.HasPrefix(key, existing))) {
+			refreshed[existing] = existingValue
+		}
+	}
+	if value != nil {
+		refreshed[key] = string(value)
+	}
+	client.pairs = refreshed
+	client.snapshotErr = client.writeSnapshot()
+}
+
+// unavailable checks if a read from the Configuration service has failed with err as it is unavailable, i.e.
+// unreachable, rather than reporting an error such as configuration not found
+func (client *cachingClient) unavailable(err error) bool {
+	return retry.IsNetworkError(err) || !client.Client.IsAlive()
+}
+
+func (client *cachingClient) readSnapshot() (map[string]string, error) {
+	contents, err := os.ReadFile(client.snapshotPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read configuration snapshot: %w", err)
+	}
+
+	values := make(map[string]any)
+	if err := json.Unmarshal(contents, &values); err != nil {
+		return nil, fmt.Errorf("unable to parse configuration snapshot %s: %w", client.snapshotPath, err)
+	}
+
+	return kvtree.Flatten("", values), nil
+}
+
+// writeSnapshot persists the cached configuration tree, replacing the snapshot file only once it has been fully
+// written so that a previous snapshot is never lost
+func (client *cachingClient) writeSnapshot() error {
+	values, err := kvtree.Expand("", client.pairs)
+	if err != nil {
+		return err
+	}
+
+	contents, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(client.snapshotPath)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("unable to create configuration snapshot directory: %w", err)
+	}
+
+	file, err := os.CreateTemp(dir, filepath.Base(client.snapshotPath)+".*")
+	if err != nil {
+		return fmt.Errorf("unable to write configuration snapshot: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(file.Name())
+	}()
+
+	_, err = file.Write(contents)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("unable to write configuration snapshot: %w", err)
+	}
+
+	if err := os.Rename(file.Name(), client.snapshotPath); err != nil {
+		return fmt.Errorf("unable to write configuration snapshot: %w", err)
+	}
+
+	return nil
+}
+
+func trimKey(name string) string {
+	return strings.Trim(name, kvtree.KeyDelimiter)
+}
@@ -0,0 +1,227 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package configuration
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-configuration/v3/configuration/mocks"
+	"github.com/edgexfoundry/go-mod-configuration/v3/pkg/types"
+)
+
+func makeUnreachableClient() *mocks.Client {
+	unreachable := errors.New("connection refused")
+
+	client := &mocks.Client{}
+	client.On("IsAlive").Return(false)
+	client.On("HasConfiguration").Return(false, unreachable)
+	client.On("HasSubConfiguration", mock.Anything).Return(false, unreachable)
+	client.On("GetConfiguration", mock.Anything).Return(nil, unreachable)
+	client.On("ConfigurationValueExists", mock.Anything).Return(false, unreachable)
+	client.On("GetConfigurationValue", mock.Anything).Return(nil, unreachable)
+
+	return client
+}
+
+func TestCachingClientServesSnapshot(t *testing.T) {
+	snapshotPath := filepath.Join(t.TempDir(), "snapshot", "configuration.json")
+
+	memory, err := NewConfigurationClient(types.ServiceConfig{Type: MemoryType, BasePath: "cache"})
+	require.NoError(t, err)
+	require.NoError(t, memory.PutConfiguration(layeredConfig{Writable: writableInfo{LogLevel: "INFO"}, Port: 8000, Host: "localhost"}, true))
+
+	client, err := NewCachingClient(memory, snapshotPath)
+	require.NoError(t, err)
+	actual, err := client.GetConfiguration(&layeredConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, &layeredConfig{Writable: writableInfo{LogLevel: "INFO"}, Port: 8000, Host: "localhost"}, actual)
+	assert.False(t, client.Stale())
+	require.FileExists(t, snapshotPath)
+
+	// the next start, with the Configuration service unreachable
+	client, err = NewCachingClient(makeUnreachableClient(), snapshotPath)
+	require.NoError(t, err)
+
+	exists, err := client.HasConfiguration()
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.True(t, client.Stale())
+
+	exists, err = client.HasSubConfiguration("Writable")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = client.ConfigurationValueExists("Missing")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	value, err := client.GetConfigurationValue("/Writable/LogLevel")
+	require.NoError(t, err)
+	assert.Equal(t, "INFO", string(value))
+
+	actual, err = client.GetConfiguration(&layeredConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, &layeredConfig{Writable: writableInfo{LogLevel: "INFO"}, Port: 8000, Host: "localhost"}, actual)
+	assert.True(t, client.Stale())
+}
+
+func TestCachingClientWithoutSnapshot(t *testing.T) {
+	client, err := NewCachingClient(makeUnreachableClient(), filepath.Join(t.TempDir(), "configuration.json"))
+	require.NoError(t, err)
+
+	_, err = client.GetConfiguration(&layeredConfig{})
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "connection refused")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.False(t, client.Stale())
+}
+
+func TestCachingClientWatchRefreshesSnapshot(t *testing.T) {
+	snapshotPath := filepath.Join(t.TempDir(), "configuration.json")
+
+	memory, err := NewConfigurationClient(types.ServiceConfig{Type: MemoryType, BasePath: "cache"})
+	require.NoError(t, err)
+	require.NoError(t, memory.PutConfiguration(layeredConfig{Writable: writableInfo{LogLevel: "INFO"}, Port: 8000}, true))
+
+	client, err := NewCachingClient(memory, snapshotPath)
+	require.NoError(t, err)
+	_, err = client.GetConfiguration(&layeredConfig{})
+	require.NoError(t, err)
+
+	updates := make(chan interface{})
+	errs := make(chan error)
+	client.WatchForChanges(updates, errs, &writableInfo{}, "Writable", nil)
+	defer client.StopWatching()
+
+	for _, expected := range []string{"INFO", "DEBUG"} {
+		select {
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting on update with LogLevel %s", expected)
+		case update := <-updates:
+			assert.Equal(t, &writableInfo{LogLevel: expected}, update)
+		case err := <-errs:
+			t.Fatalf("received WatchForChanges error: %v", err)
+		}
+
+		require.NoError(t, memory.PutConfigurationValue("Writable/LogLevel", []byte("DEBUG")))
+	}
+
+	contents, err := os.ReadFile(snapshotPath)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"Writable": {"LogLevel": "DEBUG"}, "Port": "8000", "Host": ""}`, string(contents))
+}
+
+func TestCachingClientNotFoundNotServedFromSnapshot(t *testing.T) {
+	snapshotPath := filepath.Join(t.TempDir(), "configuration.json")
+
+	memory, err := NewConfigurationClient(types.ServiceConfig{Type: MemoryType, BasePath: "cache"})
+	require.NoError(t, err)
+	require.NoError(t, memory.PutConfiguration(layeredConfig{Writable: writableInfo{LogLevel: "INFO"}, Port: 8000}, true))
+
+	client, err := NewCachingClient(memory, snapshotPath)
+	require.NoError(t, err)
+	_, err = client.GetConfiguration(&layeredConfig{})
+	require.NoError(t, err)
+
+	// the Configuration service is available but the configuration has been deleted
+	notFound := errors.New("configuration not found")
+	reachable := &mocks.Client{}
+	reachable.On("IsAlive").Return(true)
+	reachable.On("HasConfiguration").Return(false, notFound)
+	reachable.On("GetConfiguration", mock.Anything).Return(nil, notFound)
+	reachable.On("GetConfigurationValue", mock.Anything).Return(nil, notFound)
+
+	client, err = NewCachingClient(reachable, snapshotPath)
+	require.NoError(t, err)
+
+	_, err = client.HasConfiguration()
+	require.ErrorIs(t, err, notFound)
+
+	_, err = client.GetConfiguration(&layeredConfig{})
+	require.ErrorIs(t, err, notFound)
+
+	_, err = client.GetConfigurationValue("Writable/LogLevel")
+	require.ErrorIs(t, err, notFound)
+	assert.False(t, client.Stale())
+
+	// a transport error is served from the snapshot, even if the Configuration service is reported alive
+	timeout := &net.OpError{Op: "dial", Net: "tcp", Err: os.ErrDeadlineExceeded}
+	flaky := &mocks.Client{}
+	flaky.On("IsAlive").Return(true)
+	flaky.On("GetConfigurationValue", mock.Anything).Return(nil, timeout)
+
+	client, err = NewCachingClient(flaky, snapshotPath)
+	require.NoError(t, err)
+	value, err := client.GetConfigurationValue("Writable/LogLevel")
+	require.NoError(t, err)
+	assert.Equal(t, "INFO", string(value))
+	assert.True(t, client.Stale())
+}
+
+func TestCachingClientValueRefreshesSnapshot(t *testing.T) {
+	snapshotPath := filepath.Join(t.TempDir(), "configuration.json")
+
+	memory, err := NewConfigurationClient(types.ServiceConfig{Type: MemoryType, BasePath: "cache"})
+	require.NoError(t, err)
+	require.NoError(t, memory.PutConfiguration(layeredConfig{Writable: writableInfo{LogLevel: "INFO"}, Port: 8000}, true))
+
+	client, err := NewCachingClient(memory, snapshotPath)
+	require.NoError(t, err)
+	_, err = client.GetConfiguration(&layeredConfig{})
+	require.NoError(t, err)
+
+	require.NoError(t, memory.PutConfigurationValue("Writable/LogLevel", []byte("DEBUG")))
+	value, err := client.GetConfigurationValue("Writable/LogLevel")
+	require.NoError(t, err)
+	assert.Equal(t, "DEBUG", string(value))
+
+	contents, err := os.ReadFile(snapshotPath)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"Writable": {"LogLevel": "DEBUG"}, "Port": "8000", "Host": ""}`, string(contents))
+
+	// the value read last is served once the Configuration service is unavailable
+	client, err = NewCachingClient(makeUnreachableClient(), snapshotPath)
+	require.NoError(t, err)
+	value, err = client.GetConfigurationValue("Writable/LogLevel")
+	require.NoError(t, err)
+	assert.Equal(t, "DEBUG", string(value))
+	assert.True(t, client.Stale())
+}
+
+func TestCachingClientSnapshotFailure(t *testing.T) {
+	_, err := NewCachingClient(makeUnreachableClient(), "")
+	require.Error(t, err)
+
+	memory, err := NewConfigurationClient(types.ServiceConfig{Type: MemoryType, BasePath: "cache"})
+	require.NoError(t, err)
+	require.NoError(t, memory.PutConfiguration(layeredConfig{Writable: writableInfo{LogLevel: "INFO"}, Port: 8000}, true))
+
+	// the read succeeds even though the snapshot file can't be written
+	client, err := NewCachingClient(memory, "/proc/nope/snapshot.json")
+	require.NoError(t, err)
+	require.NoError(t, client.LastSnapshotError())
+
+	actual, err := client.GetConfiguration(&layeredConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, &layeredConfig{Writable: writableInfo{LogLevel: "INFO"}, Port: 8000}, actual)
+	assert.False(t, client.Stale())
+	assert.ErrorContains(t, client.LastSnapshotError(), "configuration snapshot")
+
+	require.NoError(t, memory.PutConfigurationValue("Writable/LogLevel", []byte("DEBUG")))
+	value, err := client.GetConfigurationValue("Writable/LogLevel")
+	require.NoError(t, err)
+	assert.Equal(t, "DEBUG", string(value))
+	assert.Error(t, client.LastSnapshotError())
+}
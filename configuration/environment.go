@@ -0,0 +1,123 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package configuration
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/kvtree"
+
+	"github.com/edgexfoundry/go-mod-messaging/v3/messaging"
+)
+
+// environmentClient overrides the configuration values of a Client with the environment variables named after
+// their keys
+type environmentClient struct {
+	Client
+	lookupEnv      func(name string) (string, bool)
+	lock           sync.RWMutex
+	overriddenKeys map[string]struct{}
+	watcher        *mapWatcher
+}
+
+// NewEnvironmentClient creates a Client which overrides the configuration values of client with environment
+// variables when getting the configuration and sending watch updates. The variable overriding a value is named
+// after its key following the EdgeX convention, see EnvironmentVariableName.
+// All other operations are passed on to client unchanged.
+func NewEnvironmentClient(client Client) OverrideClient {
+	envClient := environmentClient{
+		Client:         client,
+		lookupEnv:      os.LookupEnv,
+		overriddenKeys: make(map[string]struct{}),
+		watcher:        newMapWatcher(client),
+	}
+
+	return &envClient
+}
+
+// EnvironmentVariableName returns the name of the environment variable overriding the configuration value of key,
+// i.e. WRITABLE_LOGLEVEL for Writable/LogLevel and CLIENTS_CORE_DATA_HOST for Clients/core-data/Host
+func EnvironmentVariableName(key string) string {
+	replacer := strings.NewReplacer(kvtree.KeyDelimiter, "_", "-", "_", ".", "_")
+	return strings.ToUpper(replacer.Replace(strings.Trim(key, kvtree.KeyDelimiter)))
+}
+
+// GetConfiguration gets the full configuration from the wrapped Client with the environment overrides applied into
+// the target configuration struct.
+// Returns the configuration in the target struct as interface{}, which caller must cast
+func (client *environmentClient) GetConfiguration(configStruct interface{}) (interface{}, error) {
+	raw, err := client.Client.GetConfiguration(&map[string]any{})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.decode("", raw, configStruct); err != nil {
+		return nil, err
+	}
+
+	return configStruct, nil
+}
+
+// decode applies the environment overrides to the configuration map located at prefix and decodes it into target
+func (client *environmentClient) decode(prefix string, raw interface{}, target interface{}) error {
+	values, err := toMap(raw)
+	if err != nil {
+		return err
+	}
+
+	pairs := kvtree.Flatten(prefix, values)
+	client.override(pairs)
+
+	return kvtree.Decode(prefix, pairs, target)
+}
+
+// override replaces the values of pairs by the environment variables named after their keys
+func (client *environmentClient) override(pairs map[string]string) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	for key := range pairs {
+		if value, found := client.lookupEnv(EnvironmentVariableName(key)); found {
+			pairs[key] = value
+			client.overriddenKeys[key] = struct{}{}
+		}
+	}
+}
+
+// OverriddenKeys returns the sorted keys of all configuration values which have been overridden so far
+func (client *environmentClient) OverriddenKeys() []string {
+	client.lock.RLock()
+	defer client.lock.RUnlock()
+
+	keys := make([]string, 0, len(client.overriddenKeys))
+	for key := range client.overriddenKeys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// WatchForChanges sets up a watch for the target key on the wrapped Client and sends back its updates with the
+// environment overrides applied on the update channel.
+// Sends the configuration in the target struct as interface{} on updateChannel, which caller must cast
+func (client *environmentClient) WatchForChanges(updateChannel chan<- interface{}, errorChannel chan<- error, configuration interface{}, waitKey string, msgClient messaging.MessageClient) {
+	client.watcher.watch(updateChannel, errorChannel, configuration, waitKey, msgClient, client.decode)
+}
+
+// StopWatching causes all WatchForChanges processing of the wrapped Client to stop and waits until they have stopped.
+func (client *environmentClient) StopWatching() {
+	client.watcher.stop()
+}
+
+// Close stops all watches and closes the wrapped Client, which must not be used afterward
+func (client *environmentClient) Close() error {
+	client.watcher.stop()
+	return closeClient(client.Client)
+}
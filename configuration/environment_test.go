@@ -0,0 +1,86 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package configuration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-configuration/v3/pkg/types"
+)
+
+func TestEnvironmentVariableName(t *testing.T) {
+	tests := map[string]string{
+		"Writable/LogLevel":         "WRITABLE_LOGLEVEL",
+		"/Port/":                    "PORT",
+		"Clients/core-data/Host":    "CLIENTS_CORE_DATA_HOST",
+		"Database/Options/tls.mode": "DATABASE_OPTIONS_TLS_MODE",
+	}
+
+	for key, expected := range tests {
+		t.Run(key, func(t *testing.T) {
+			assert.Equal(t, expected, EnvironmentVariableName(key))
+		})
+	}
+}
+
+func makeEnvironmentClient(t *testing.T) (Client, OverrideClient) {
+	memory, err := NewConfigurationClient(types.ServiceConfig{Type: MemoryType, BasePath: "env"})
+	require.NoError(t, err)
+	require.NoError(t, memory.PutConfiguration(layeredConfig{Writable: writableInfo{LogLevel: "INFO"}, Port: 8000, Host: "localhost"}, true))
+
+	return memory, NewEnvironmentClient(memory)
+}
+
+func TestEnvironmentClientGetConfiguration(t *testing.T) {
+	t.Setenv("WRITABLE_LOGLEVEL", "DEBUG")
+	t.Setenv("PORT", "59881")
+
+	_, client := makeEnvironmentClient(t)
+	assert.Empty(t, client.OverriddenKeys())
+
+	actual, err := client.GetConfiguration(&layeredConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, &layeredConfig{Writable: writableInfo{LogLevel: "DEBUG"}, Port: 59881, Host: "localhost"}, actual)
+	assert.Equal(t, []string{"Port", "Writable/LogLevel"}, client.OverriddenKeys())
+
+	// values are only overridden on reading the configuration, never stored
+	value, err := client.GetConfigurationValue("Port")
+	require.NoError(t, err)
+	assert.Equal(t, "8000", string(value))
+}
+
+func TestEnvironmentClientWatchForChanges(t *testing.T) {
+	t.Setenv("WRITABLE_LOGLEVEL", "DEBUG")
+
+	memory, client := makeEnvironmentClient(t)
+	require.NoError(t, memory.PutConfigurationValue("Writable/InsecureSecrets", []byte("none")))
+
+	updates := make(chan interface{})
+	errs := make(chan error)
+	client.WatchForChanges(updates, errs, &map[string]any{}, "Writable", nil)
+	defer client.StopWatching()
+
+	expectUpdate := func(expected map[string]any) {
+		select {
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting on update %v", expected)
+		case update := <-updates:
+			assert.Equal(t, &expected, update)
+		case err := <-errs:
+			t.Fatalf("received WatchForChanges error: %v", err)
+		}
+	}
+
+	expectUpdate(map[string]any{"LogLevel": "DEBUG", "InsecureSecrets": "none"})
+	assert.Equal(t, []string{"Writable/LogLevel"}, client.OverriddenKeys())
+
+	require.NoError(t, memory.PutConfigurationValue("Writable/InsecureSecrets", []byte("some")))
+	expectUpdate(map[string]any{"LogLevel": "DEBUG", "InsecureSecrets": "some"})
+}
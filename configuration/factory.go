@@ -19,27 +19,148 @@ package configuration
 
 import (
 	"fmt"
+	"sort"
+	"sync"
 
 	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/consul"
+	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/file"
 	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/keeper"
+	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/memory"
 	"github.com/edgexfoundry/go-mod-configuration/v3/pkg/types"
 )
 
+const (
+	ConsulType = "consul"
+	KeeperType = "keeper"
+	FileType   = "file"
+	MemoryType = "memory"
+)
+
+// ProviderFactory creates a Configuration Client for the given service configuration
+type ProviderFactory func(config types.ServiceConfig) (Client, error)
+
+var (
+	providersMutex sync.RWMutex
+	providers      = make(map[string]ProviderFactory)
+)
+
+func init() {
+	mustRegisterProvider(ConsulType, newConsulClient)
+	mustRegisterProvider(KeeperType, newKeeperClient)
+	mustRegisterProvider(FileType, newFileClient)
+	mustRegisterProvider(MemoryType, newMemoryClient)
+}
+
+// RegisterProvider registers the factory used to create Configuration Clients of the given type.
+// The type is matched against ServiceConfig.Type, i.e. the scheme of the provider URL used with PopulateFromUrl.
+// An error is returned if a provider of the same type has already been registered.
+func RegisterProvider(providerType string, factory ProviderFactory) error {
+	if providerType == "" {
+		return fmt.Errorf("unable to register Configuration provider: type not set")
+	}
+
+	if factory == nil {
+		return fmt.Errorf("unable to register Configuration provider '%s': factory is nil", providerType)
+	}
+
+	providersMutex.Lock()
+	defer providersMutex.Unlock()
+
+	if _, exists := providers[providerType]; exists {
+		return fmt.Errorf("configuration provider type '%s' is already registered", providerType)
+	}
+
+	providers[providerType] = factory
+	return nil
+}
+
+// RegisteredProviders returns the sorted list of registered Configuration provider types
+func RegisteredProviders() []string {
+	providersMutex.RLock()
+	defer providersMutex.RUnlock()
+
+	list := make([]string, 0, len(providers))
+	for providerType := range providers {
+		list = append(list, providerType)
+	}
+	sort.Strings(list)
+
+	return list
+}
+
+// NewConfigurationClient creates a Configuration Client using the provider registered for config.Type
 func NewConfigurationClient(config types.ServiceConfig) (Client, error) {
+	providersMutex.RLock()
+	factory, exists := providers[config.Type]
+	providersMutex.RUnlock()
 
-	if config.Host == "" || config.Port == 0 {
-		return nil, fmt.Errorf("unable to create Configuration Client: Configuration service host and/or port or serviceKey not set")
-	}
-
-	switch config.Type {
-	case "consul":
-		var err error
-		client, err := consul.NewConsulClient(config)
-		return client, err
-	case "keeper":
-		client := keeper.NewKeeperClient(config)
-		return client, nil
-	default:
+	if !exists {
 		return nil, fmt.Errorf("unknown configuration client type '%s' requested", config.Type)
 	}
+
+	return factory(config)
+}
+
+// closeClient closes the client if it holds resources which must be released, i.e. it implements ClosableClient,
+// otherwise it only stops its watches
+func closeClient(client Client) error {
+	if closable, ok := client.(ClosableClient); ok {
+		return closable.Close()
+	}
+
+	client.StopWatching()
+	return nil
+}
+
+func mustRegisterProvider(providerType string, factory ProviderFactory) {
+	if err := RegisterProvider(providerType, factory); err != nil {
+		panic(err)
+	}
+}
+
+func validateHostAndPort(config types.ServiceConfig) error {
+	if config.Host == "" || config.Port == 0 {
+		return fmt.Errorf("unable to create Configuration Client: Configuration service host and/or port or serviceKey not set")
+	}
+
+	return nil
+}
+
+func newConsulClient(config types.ServiceConfig) (Client, error) {
+	if err := validateHostAndPort(config); err != nil {
+		return nil, err
+	}
+
+	client, err := consul.NewConsulClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func newKeeperClient(config types.ServiceConfig) (Client, error) {
+	if err := validateHostAndPort(config); err != nil {
+		return nil, err
+	}
+
+	client, err := keeper.NewKeeperClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func newFileClient(config types.ServiceConfig) (Client, error) {
+	client, err := file.NewFileClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func newMemoryClient(config types.ServiceConfig) (Client, error) {
+	return memory.NewMemoryClient(config), nil
 }
@@ -17,10 +17,14 @@
 package configuration
 
 import (
+	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/edgexfoundry/go-mod-configuration/v3/configuration/mocks"
 	"github.com/edgexfoundry/go-mod-configuration/v3/pkg/types"
 )
 
@@ -51,3 +55,178 @@ func TestNewClientBogusType(t *testing.T) {
 		t.Fatal()
 	}
 }
+
+func TestNewClientKeeper(t *testing.T) {
+
+	config.Type = "keeper"
+
+	client, err := NewConfigurationClient(config)
+	if assert.Nil(t, err, "New Configuration client failed: ", err) == false {
+		t.Fatal()
+	}
+
+	assert.NotNil(t, client)
+}
+
+func TestNewClientMissingHost(t *testing.T) {
+	noHostConfig := types.ServiceConfig{
+		Port: 8500,
+		Type: "consul",
+	}
+
+	_, err := NewConfigurationClient(noHostConfig)
+	assert.Error(t, err, "Expected host not set error")
+}
+
+func TestRegisterProvider(t *testing.T) {
+	customType := "custom"
+	expected := &mocks.Client{}
+
+	err := RegisterProvider(customType, func(config types.ServiceConfig) (Client, error) {
+		return expected, nil
+	})
+	require.NoError(t, err)
+	defer func() {
+		providersMutex.Lock()
+		delete(providers, customType)
+		providersMutex.Unlock()
+	}()
+
+	assert.Contains(t, RegisteredProviders(), customType)
+
+	client, err := NewConfigurationClient(types.ServiceConfig{Type: customType})
+	require.NoError(t, err)
+	assert.Equal(t, expected, client)
+}
+
+func TestRegisterProviderErrors(t *testing.T) {
+	factory := func(config types.ServiceConfig) (Client, error) {
+		return nil, nil
+	}
+
+	tests := []struct {
+		name         string
+		providerType string
+		factory      ProviderFactory
+	}{
+		{"Duplicate", ConsulType, factory},
+		{"Empty type", "", factory},
+		{"Nil factory", "nil-factory", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := RegisterProvider(test.providerType, test.factory)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRegisteredProviders(t *testing.T) {
+	actual := RegisteredProviders()
+	assert.Contains(t, actual, ConsulType)
+	assert.Contains(t, actual, KeeperType)
+}
+
+func TestNewClientFile(t *testing.T) {
+	fileConfig := types.ServiceConfig{
+		Type:     FileType,
+		Path:     t.TempDir(),
+		BasePath: "config",
+	}
+
+	client, err := NewConfigurationClient(fileConfig)
+	require.NoError(t, err)
+	assert.True(t, client.IsAlive())
+}
+
+func TestClientCapabilities(t *testing.T) {
+	// implements reports whether the client implements the capability interface
+	type implements func(client Client) bool
+	capabilities := map[string]implements{
+		"ClientWithContext": func(client Client) bool { _, ok := client.(ClientWithContext); return ok },
+		"BatchClient":       func(client Client) bool { _, ok := client.(BatchClient); return ok },
+		"VersionedClient":   func(client Client) bool { _, ok := client.(VersionedClient); return ok },
+		"EventClient":       func(client Client) bool { _, ok := client.(EventClient); return ok },
+		"WatcherClient":     func(client Client) bool { _, ok := client.(WatcherClient); return ok },
+		"ClosableClient":    func(client Client) bool { _, ok := client.(ClosableClient); return ok },
+	}
+
+	tests := []struct {
+		providerType string
+		config       types.ServiceConfig
+		expected     []string
+	}{
+		{
+			ConsulType,
+			types.ServiceConfig{Type: ConsulType, Host: "localhost", Port: 8500, BasePath: "config"},
+			[]string{"ClientWithContext", "BatchClient", "VersionedClient", "EventClient", "WatcherClient", "ClosableClient"},
+		},
+		{
+			KeeperType,
+			types.ServiceConfig{Type: KeeperType, Host: "localhost", Port: 59883, BasePath: "config"},
+			[]string{"ClientWithContext", "BatchClient", "VersionedClient", "EventClient", "WatcherClient", "ClosableClient"},
+		},
+		{
+			FileType,
+			types.ServiceConfig{Type: FileType, Path: t.TempDir(), BasePath: "config"},
+			[]string{"WatcherClient"},
+		},
+		{
+			MemoryType,
+			types.ServiceConfig{Type: MemoryType, BasePath: "config"},
+			[]string{"WatcherClient"},
+		},
+	}
+
+	for _, test := range tests {
+		client, err := NewConfigurationClient(test.config)
+		require.NoError(t, err)
+
+		for name, implemented := range capabilities {
+			t.Run(test.providerType+"/"+name, func(t *testing.T) {
+				expected := slices.Contains(test.expected, name)
+				assert.Equal(t, expected, implemented(client), "%s client implementing %s", test.providerType, name)
+			})
+		}
+	}
+}
+
+// closableClient counts the calls of Close
+type closableClient struct {
+	*mocks.Client
+	closed int
+}
+
+func (client *closableClient) Close() error {
+	client.closed++
+	return nil
+}
+
+func TestCloseWrappedClients(t *testing.T) {
+	newClosable := func() *closableClient {
+		client := &closableClient{Client: &mocks.Client{}}
+		client.On("StopWatching").Return()
+		return client
+	}
+
+	closable := newClosable()
+	require.NoError(t, NewEnvironmentClient(closable).(ClosableClient).Close())
+	assert.Equal(t, 1, closable.closed)
+
+	closable = newClosable()
+	caching, err := NewCachingClient(closable, filepath.Join(t.TempDir(), "configuration.json"))
+	require.NoError(t, err)
+	require.NoError(t, caching.(ClosableClient).Close())
+	assert.Equal(t, 1, closable.closed)
+
+	// layers which aren't closable only have their watches stopped
+	closable = newClosable()
+	other := &mocks.Client{}
+	other.On("StopWatching").Return()
+	layered, err := NewLayeredClient(closable, other, closable)
+	require.NoError(t, err)
+	require.NoError(t, layered.(ClosableClient).Close())
+	assert.Equal(t, 1, closable.closed)
+	other.AssertCalled(t, "StopWatching")
+}
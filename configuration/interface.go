@@ -17,7 +17,13 @@
 
 package configuration
 
-import "github.com/edgexfoundry/go-mod-messaging/v3/messaging"
+import (
+	"context"
+
+	"github.com/edgexfoundry/go-mod-configuration/v3/pkg/types"
+
+	"github.com/edgexfoundry/go-mod-messaging/v3/messaging"
+)
 
 type Client interface {
 	// HasConfiguration checks to see if the Configuration service contains the service's configuration.
@@ -63,4 +69,151 @@ type Client interface {
 
 	// GetConfigurationKeys returns all keys under name
 	GetConfigurationKeys(name string) ([]string, error)
+
+	// DeleteConfigurationValue removes a specific configuration value from the Configuration service.
+	// No error is returned if the value doesn't exist.
+	DeleteConfigurationValue(name string) error
+
+	// DeleteSubConfiguration removes all configuration values located under name from the Configuration service.
+	// No error is returned if the sub configuration doesn't exist.
+	DeleteSubConfiguration(name string) error
+}
+
+// ClientWithContext is implemented by Clients whose calls to the Configuration service can be cancelled or bounded
+// by a deadline via the passed context.Context. The methods behave the same as their Client counterparts.
+type ClientWithContext interface {
+	Client
+
+	// HasConfigurationCtx checks to see if the Configuration service contains the service's configuration.
+	HasConfigurationCtx(ctx context.Context) (bool, error)
+
+	// HasSubConfigurationCtx checks to see if the Configuration service contains the service's sub configuration.
+	HasSubConfigurationCtx(ctx context.Context, name string) (bool, error)
+
+	// PutConfigurationMapCtx puts a full map configuration into the Configuration service
+	PutConfigurationMapCtx(ctx context.Context, configuration map[string]any, overwrite bool) error
+
+	// PutConfigurationCtx puts a full configuration struct into the Configuration service
+	PutConfigurationCtx(ctx context.Context, configStruct interface{}, overwrite bool) error
+
+	// GetConfigurationCtx gets the full configuration from the Configuration service into the target configuration struct.
+	GetConfigurationCtx(ctx context.Context, configStruct interface{}) (interface{}, error)
+
+	// WatchForChangesCtx sets up a watch for the target key and send back updates on the update channel.
+	// The watch stops when either the context is done or StopWatching is called.
+	WatchForChangesCtx(ctx context.Context, updateChannel chan<- interface{}, errorChannel chan<- error, configuration interface{}, waitKey string, msgClient messaging.MessageClient)
+
+	// IsAliveCtx simply checks if Configuration service is up and running at the configured URL
+	IsAliveCtx(ctx context.Context) bool
+
+	// ConfigurationValueExistsCtx checks if a configuration value exists in the Configuration service
+	ConfigurationValueExistsCtx(ctx context.Context, name string) (bool, error)
+
+	// GetConfigurationValueCtx gets a specific configuration value from the Configuration service
+	GetConfigurationValueCtx(ctx context.Context, name string) ([]byte, error)
+
+	// GetConfigurationValueByFullPathCtx gets a specific configuration value from the Configuration service
+	GetConfigurationValueByFullPathCtx(ctx context.Context, fullPath string) ([]byte, error)
+
+	// PutConfigurationValueCtx puts a specific configuration value into the Configuration service
+	PutConfigurationValueCtx(ctx context.Context, name string, value []byte) error
+
+	// GetConfigurationKeysCtx returns all keys under name
+	GetConfigurationKeysCtx(ctx context.Context, name string) ([]string, error)
+
+	// DeleteConfigurationValueCtx removes a specific configuration value from the Configuration service
+	DeleteConfigurationValueCtx(ctx context.Context, name string) error
+
+	// DeleteSubConfigurationCtx removes all configuration values located under name from the Configuration service
+	DeleteSubConfigurationCtx(ctx context.Context, name string) error
+}
+
+// BatchClient is implemented by Clients which write a full configuration in as few requests as possible.
+// The writes are atomic as far as the Configuration service supports it, so that a failure doesn't leave
+// a partially stored configuration behind.
+type BatchClient interface {
+	Client
+
+	// PutConfigurationMapBatch puts a full map configuration into the Configuration service and reports
+	// which keys have been written, skipped or failed.
+	PutConfigurationMapBatch(ctx context.Context, configuration map[string]any, overwrite bool) (types.PutResult, error)
+
+	// PutConfigurationBatch puts a full configuration struct into the Configuration service and reports
+	// which keys have been written, skipped or failed.
+	PutConfigurationBatch(ctx context.Context, configStruct interface{}, overwrite bool) (types.PutResult, error)
+}
+
+// VersionedClient is implemented by Clients which support compare-and-swap writes of configuration values,
+// so that concurrent updates of the same value don't silently overwrite each other.
+// A version of 0 stands for a value which doesn't exist.
+type VersionedClient interface {
+	Client
+
+	// GetConfigurationValueWithVersion gets a specific configuration value along with its current version
+	GetConfigurationValueWithVersion(name string) ([]byte, uint64, error)
+
+	// GetConfigurationValueWithVersionCtx gets a specific configuration value along with its current version
+	GetConfigurationValueWithVersionCtx(ctx context.Context, name string) ([]byte, uint64, error)
+
+	// PutConfigurationValueCAS puts a specific configuration value only if its version still is expectedVersion.
+	// A *types.ConflictError is returned if the value has been changed in the meantime.
+	PutConfigurationValueCAS(name string, value []byte, expectedVersion uint64) error
+
+	// PutConfigurationValueCASCtx puts a specific configuration value only if its version still is expectedVersion.
+	// A *types.ConflictError is returned if the value has been changed in the meantime.
+	PutConfigurationValueCASCtx(ctx context.Context, name string, value []byte, expectedVersion uint64) error
+}
+
+// EventClient is implemented by Clients which report each change of the watched configuration values as an event,
+// rather than the whole re-decoded configuration.
+type EventClient interface {
+	Client
+
+	// WatchForEvents sets up a watch for the configuration values under waitKey and sends a ChangeEvent on
+	// eventChannel for every value added, modified or deleted once the watch is established.
+	// The watch stops when either the context is done or StopWatching is called.
+	WatchForEvents(ctx context.Context, eventChannel chan<- types.ChangeEvent, errorChannel chan<- error, waitKey string, msgClient messaging.MessageClient)
+}
+
+// WatcherClient is implemented by Clients which return a handle for each watch, so that a single watch can be
+// stopped without affecting the other watches of the Client.
+type WatcherClient interface {
+	Client
+
+	// Watch sets up a watch for the target key like WatchForChanges and returns its handle.
+	// The watch stops when either the handle is stopped, the context is done or StopWatching is called.
+	Watch(ctx context.Context, updateChannel chan<- interface{}, errorChannel chan<- error, configuration interface{}, waitKey string, msgClient messaging.MessageClient) types.Watcher
+}
+
+// OverrideClient is implemented by Clients which override the stored configuration values, i.e. with environment
+// variables, when getting the configuration or sending watch updates.
+type OverrideClient interface {
+	Client
+
+	// OverriddenKeys returns the sorted keys, relative to the service's base path, of all configuration values
+	// which have been overridden so far
+	OverriddenKeys() []string
+}
+
+// ClosableClient is implemented by Clients which hold resources beyond their watches, i.e. the background renewal
+// of the Access Token, which must be released once the Client is no longer used.
+type ClosableClient interface {
+	Client
+
+	// Close stops all watches and releases the resources held by the Client, which must not be used afterward
+	Close() error
+}
+
+// CachingClient is implemented by Clients which serve reads from the last successfully loaded configuration when
+// the Configuration service is unavailable.
+type CachingClient interface {
+	Client
+
+	// Stale reports whether the last read has been served from the cached configuration rather than the
+	// Configuration service
+	Stale() bool
+
+	// LastSnapshotError returns the error the cached configuration has last failed to be persisted with, or nil if it
+	// has been persisted since. Such a failure doesn't fail the read which has refreshed the cache.
+	LastSnapshotError() error
 }
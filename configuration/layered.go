@@ -0,0 +1,397 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package configuration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/kvtree"
+
+	"github.com/edgexfoundry/go-mod-messaging/v3/messaging"
+)
+
+// layeredClient stacks several Clients, called layers, into a single Client
+type layeredClient struct {
+	// layers are ordered from the lowest to the highest precedence
+	layers          []Client
+	target          Client
+	watchMutex      sync.Mutex
+	watchingDoneCtx context.Context
+	watchingDone    context.CancelFunc
+	watchingWait    sync.WaitGroup
+}
+
+// NewLayeredClient creates a Client which stacks the layers, given from the lowest to the highest precedence.
+// Values are read from the layer with the highest precedence holding them, the full configuration is decoded from
+// the values of all layers merged, and watches send the merged configuration whenever any layer has changed.
+// All writes and deletes go to target, which must be one of the layers.
+func NewLayeredClient(target Client, layers ...Client) (Client, error) {
+	if len(layers) == 0 {
+		return nil, errors.New("at least one layer is required for a layered configuration client")
+	}
+	if target == nil || !containsLayer(layers, target) {
+		return nil, errors.New("the target of a layered configuration client must be one of its layers")
+	}
+
+	client := layeredClient{
+		layers: layers,
+		target: target,
+	}
+
+	client.watchingDoneCtx, client.watchingDone = context.WithCancel(context.Background())
+
+	return &client, nil
+}
+
+func containsLayer(layers []Client, target Client) bool {
+	for _, layer := range layers {
+		if layer == target {
+			return true
+		}
+	}
+	return false
+}
+
+// HasConfiguration checks to see if any layer contains the service's configuration.
+func (client *layeredClient) HasConfiguration() (bool, error) {
+	for index, layer := range client.layers {
+		exists, err := layer.HasConfiguration()
+		if err != nil {
+			return false, layerError(index, err)
+		}
+		if exists {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// HasSubConfiguration checks to see if any layer contains the service's sub configuration.
+func (client *layeredClient) HasSubConfiguration(name string) (bool, error) {
+	for index, layer := range client.layers {
+		exists, err := layer.HasSubConfiguration(name)
+		if err != nil {
+			return false, layerError(index, err)
+		}
+		if exists {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// PutConfigurationMap puts a full map configuration into the target layer
+func (client *layeredClient) PutConfigurationMap(configuration map[string]any, overwrite bool) error {
+	return client.target.PutConfigurationMap(configuration, overwrite)
+}
+
+// PutConfiguration puts a full configuration struct into the target layer
+func (client *layeredClient) PutConfiguration(configStruct interface{}, overwrite bool) error {
+	return client.target.PutConfiguration(configStruct, overwrite)
+}
+
+// GetConfiguration gets the full configuration merged from all layers into the target configuration struct.
+// Returns the configuration in the target struct as interface{}, which caller must cast
+func (client *layeredClient) GetConfiguration(configStruct interface{}) (interface{}, error) {
+	pairs, err := client.mergedPairs()
+	if err != nil {
+		return nil, err
+	}
+	if len(pairs) == 0 {
+		return nil, errors.New("none of the layers contains configuration")
+	}
+
+	if err := kvtree.Decode("", pairs, configStruct); err != nil {
+		return nil, err
+	}
+
+	return configStruct, nil
+}
+
+// mergedPairs gets the full configuration of every layer as key/value pairs, where the values of a layer replace
+// those of the layers with a lower precedence
+func (client *layeredClient) mergedPairs() (map[string]string, error) {
+	merged := make(map[string]string)
+	for index, layer := range client.layers {
+		exists, err := layer.HasConfiguration()
+		if err != nil {
+			return nil, layerError(index, err)
+		}
+		if !exists {
+			continue
+		}
+
+		raw, err := layer.GetConfiguration(&map[string]any{})
+		if err != nil {
+			return nil, layerError(index, err)
+		}
+
+		values, err := toMap(raw)
+		if err != nil {
+			return nil, layerError(index, err)
+		}
+
+		for key, value := range kvtree.Flatten("", values) {
+			// a value replaces the whole directory at its key, and a directory replaces the value at its path
+			for existing := range merged {
+				if existing != key && (kvtree.HasPrefix(existing, key) || kvtree.HasPrefix(key, existing)) {
+					delete(merged, existing)
+				}
+			}
+			merged[key] = value
+		}
+	}
+
+	return merged, nil
+}
+
+// toMap returns the configuration map a Client has decoded into a *map[string]any target
+func toMap(raw interface{}) (map[string]any, error) {
+	switch raw := raw.(type) {
+	case *map[string]any:
+		return *raw, nil
+	case map[string]any:
+		return raw, nil
+	default:
+		return nil, fmt.Errorf("unexpected configuration of type %T", raw)
+	}
+}
+
+// WatchForChanges sets up a watch for the target key on every layer and sends back the configuration merged from
+// all layers on the update channel, once the watch is set up and whenever the merged configuration has changed.
+// The msgClient is passed to the watch of every layer.
+// Sends the configuration in the target struct as interface{} on updateChannel, which caller must cast
+func (client *layeredClient) WatchForChanges(updateChannel chan<- interface{}, errorChannel chan<- error, configuration interface{}, waitKey string, msgClient messaging.MessageClient) {
+	client.watchMutex.Lock()
+	defer client.watchMutex.Unlock()
+
+	// the updates of the layers are only the signal to merge the configuration again
+	layerUpdates := make(chan interface{})
+	layerErrors := make(chan error)
+
+	ctx := client.watchingDoneCtx
+	prefix := strings.Trim(waitKey, kvtree.KeyDelimiter)
+
+	// the merge is started first and never blocks the layers on signalling, since they may do so while their watch is
+	// set up, i.e. before the caller is able to read the update or the errors pending
+	client.watchingWait.Add(1)
+	go func() {
+		defer client.watchingWait.Done()
+
+		var previous map[string]string
+		// changed is set once a layer has signalled a change which hasn't been merged yet
+		changed := true
+		// updates is set while the merged configuration is pending
+		var updates chan<- interface{}
+		var pendingErrors []error
+
+		for {
+			if changed && updates == nil {
+				changed = false
+				pairs, err := client.mergedPairs()
+				if err != nil {
+					pendingErrors = append(pendingErrors, err)
+				} else if subtree := kvtree.Subtree(prefix, pairs); previous == nil || !maps.Equal(previous, subtree) {
+					previous = subtree
+					if err := kvtree.Decode(prefix, subtree, configuration); err != nil {
+						pendingErrors = append(pendingErrors, err)
+					} else {
+						updates = updateChannel
+					}
+				}
+			}
+
+			var errs chan<- error
+			var pendingErr error
+			if len(pendingErrors) > 0 {
+				errs = errorChannel
+				pendingErr = pendingErrors[0]
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-layerUpdates:
+				changed = true
+			case err := <-layerErrors:
+				pendingErrors = append(pendingErrors, err)
+			case updates <- configuration:
+				updates = nil
+			case errs <- pendingErr:
+				pendingErrors = pendingErrors[1:]
+			}
+		}
+	}()
+
+	for _, layer := range client.layers {
+		layer.WatchForChanges(layerUpdates, layerErrors, newTarget(configuration), waitKey, msgClient)
+	}
+}
+
+// newTarget returns a new value of the type configuration points to, so that the watches of the layers don't decode
+// into the configuration sent back by the layered watch
+func newTarget(configuration interface{}) interface{} {
+	value := reflect.ValueOf(configuration)
+	if value.Kind() != reflect.Pointer || value.IsNil() {
+		return &map[string]any{}
+	}
+
+	return reflect.New(value.Type().Elem()).Interface()
+}
+
+// send sends the value on the channel unless ctx is done first, in which case false is returned
+func send[V any](ctx context.Context, channel chan<- V, value V) bool {
+	select {
+	case channel <- value:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// StopWatching causes all WatchForChanges processing of every layer to stop and waits until they have stopped.
+func (client *layeredClient) StopWatching() {
+	client.watchMutex.Lock()
+	defer client.watchMutex.Unlock()
+
+	// the layers are stopped first, since they might be blocked on signalling a change
+	for _, layer := range client.layers {
+		layer.StopWatching()
+	}
+
+	client.watchingDone()
+	client.watchingWait.Wait()
+
+	client.watchingDoneCtx, client.watchingDone = context.WithCancel(context.Background())
+}
+
+// Close stops all watches and closes every layer, which must not be used afterward
+func (client *layeredClient) Close() error {
+	client.StopWatching()
+
+	var errs []error
+	for _, layer := range client.layers {
+		errs = append(errs, closeClient(layer))
+	}
+
+	return errors.Join(errs...)
+}
+
+// IsAlive checks if the Configuration services of all layers are up and running
+func (client *layeredClient) IsAlive() bool {
+	for _, layer := range client.layers {
+		if !layer.IsAlive() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ConfigurationValueExists checks if a configuration value exists in any layer
+func (client *layeredClient) ConfigurationValueExists(name string) (bool, error) {
+	_, found, err := client.findLayer(name)
+	return found, err
+}
+
+// GetConfigurationValue gets a specific configuration value from the layer with the highest precedence holding it
+func (client *layeredClient) GetConfigurationValue(name string) ([]byte, error) {
+	layer, found, err := client.findLayer(name)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	return layer.GetConfigurationValue(name)
+}
+
+// findLayer returns the layer with the highest precedence holding the configuration value
+func (client *layeredClient) findLayer(name string) (Client, bool, error) {
+	for index := len(client.layers) - 1; index >= 0; index-- {
+		exists, err := client.layers[index].ConfigurationValueExists(name)
+		if err != nil {
+			return nil, false, layerError(index, err)
+		}
+		if exists {
+			return client.layers[index], true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
+// GetConfigurationValueByFullPath gets a specific configuration value from the layer with the highest precedence
+// returning it. Full paths are specific to the Configuration service of each layer.
+func (client *layeredClient) GetConfigurationValueByFullPath(fullPath string) ([]byte, error) {
+	var errs []error
+	for index := len(client.layers) - 1; index >= 0; index-- {
+		value, err := client.layers[index].GetConfigurationValueByFullPath(fullPath)
+		if err != nil {
+			errs = append(errs, layerError(index, err))
+			continue
+		}
+		if value != nil {
+			return value, nil
+		}
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// PutConfigurationValue puts a specific configuration value into the target layer
+func (client *layeredClient) PutConfigurationValue(name string, value []byte) error {
+	return client.target.PutConfigurationValue(name, value)
+}
+
+// GetConfigurationKeys returns the keys under name of all layers, without duplicates
+func (client *layeredClient) GetConfigurationKeys(name string) ([]string, error) {
+	keys := make(map[string]struct{})
+	for index, layer := range client.layers {
+		layerKeys, err := layer.GetConfigurationKeys(name)
+		if err != nil {
+			return nil, layerError(index, err)
+		}
+		for _, key := range layerKeys {
+			keys[key] = struct{}{}
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	list := make([]string, 0, len(keys))
+	for key := range keys {
+		list = append(list, key)
+	}
+	sort.Strings(list)
+
+	return list, nil
+}
+
+// DeleteConfigurationValue removes a specific configuration value from the target layer.
+// The value of other layers, if any, is then read instead.
+func (client *layeredClient) DeleteConfigurationValue(name string) error {
+	return client.target.DeleteConfigurationValue(name)
+}
+
+// DeleteSubConfiguration removes all configuration values located under name from the target layer.
+// The values of other layers, if any, are then read instead.
+func (client *layeredClient) DeleteSubConfiguration(name string) error {
+	return client.target.DeleteSubConfiguration(name)
+}
+
+func layerError(index int, err error) error {
+	return fmt.Errorf("configuration layer %d: %w", index, err)
+}
@@ -0,0 +1,222 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package configuration
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-configuration/v3/pkg/types"
+
+	"github.com/edgexfoundry/go-mod-messaging/v3/messaging"
+)
+
+type layeredConfig struct {
+	Writable writableInfo
+	Port     int
+	Host     string
+}
+
+func makeLayers(t *testing.T) (Client, Client) {
+	common, err := NewConfigurationClient(types.ServiceConfig{Type: MemoryType, BasePath: "common"})
+	require.NoError(t, err)
+	service, err := NewConfigurationClient(types.ServiceConfig{Type: MemoryType, BasePath: "service"})
+	require.NoError(t, err)
+
+	require.NoError(t, common.PutConfiguration(layeredConfig{Writable: writableInfo{LogLevel: "INFO"}, Port: 8000, Host: "localhost"}, true))
+	require.NoError(t, service.PutConfigurationMap(map[string]any{"Port": 59881}, true))
+
+	return common, service
+}
+
+func TestNewLayeredClient(t *testing.T) {
+	common, service := makeLayers(t)
+
+	_, err := NewLayeredClient(service)
+	assert.Error(t, err)
+
+	_, err = NewLayeredClient(nil, common, service)
+	assert.Error(t, err)
+
+	_, err = NewLayeredClient(service, common)
+	assert.Error(t, err)
+
+	client, err := NewLayeredClient(service, common, service)
+	require.NoError(t, err)
+	assert.True(t, client.IsAlive())
+}
+
+func TestLayeredClientReads(t *testing.T) {
+	common, service := makeLayers(t)
+	client, err := NewLayeredClient(service, common, service)
+	require.NoError(t, err)
+
+	exists, err := client.HasConfiguration()
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = client.HasSubConfiguration("Writable")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	value, err := client.GetConfigurationValue("Port")
+	require.NoError(t, err)
+	assert.Equal(t, "59881", string(value))
+
+	value, err = client.GetConfigurationValue("Writable/LogLevel")
+	require.NoError(t, err)
+	assert.Equal(t, "INFO", string(value))
+
+	value, err = client.GetConfigurationValue("Missing")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	value, err = client.GetConfigurationValueByFullPath("common/Host")
+	require.NoError(t, err)
+	assert.Equal(t, "localhost", string(value))
+
+	keys, err := client.GetConfigurationKeys("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"common/Host", "common/Port", "common/Writable/LogLevel", "service/Port"}, keys)
+
+	actual, err := client.GetConfiguration(&layeredConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, &layeredConfig{Writable: writableInfo{LogLevel: "INFO"}, Port: 59881, Host: "localhost"}, actual)
+}
+
+func TestLayeredClientWrites(t *testing.T) {
+	common, service := makeLayers(t)
+	client, err := NewLayeredClient(service, common, service)
+	require.NoError(t, err)
+
+	require.NoError(t, client.PutConfigurationValue("Writable/LogLevel", []byte("DEBUG")))
+
+	value, err := client.GetConfigurationValue("Writable/LogLevel")
+	require.NoError(t, err)
+	assert.Equal(t, "DEBUG", string(value))
+
+	value, err = common.GetConfigurationValue("Writable/LogLevel")
+	require.NoError(t, err)
+	assert.Equal(t, "INFO", string(value), "lower layer must not be written")
+
+	// deleting from the target reveals the lower layer again
+	require.NoError(t, client.DeleteConfigurationValue("Port"))
+
+	value, err = client.GetConfigurationValue("Port")
+	require.NoError(t, err)
+	assert.Equal(t, "8000", string(value))
+}
+
+func TestLayeredClientMergeReplacesDirectory(t *testing.T) {
+	common, service := makeLayers(t)
+	require.NoError(t, service.PutConfigurationMap(map[string]any{"Writable": "none"}, true))
+
+	client, err := NewLayeredClient(service, common, service)
+	require.NoError(t, err)
+
+	actual, err := client.GetConfiguration(&map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, &map[string]any{"Writable": "none", "Port": "59881", "Host": "localhost"}, actual)
+}
+
+func TestLayeredClientWatchForChanges(t *testing.T) {
+	common, service := makeLayers(t)
+	client, err := NewLayeredClient(service, common, service)
+	require.NoError(t, err)
+
+	updates := make(chan interface{})
+	errs := make(chan error)
+	client.WatchForChanges(updates, errs, &writableInfo{}, "Writable", nil)
+	defer client.StopWatching()
+
+	expectUpdate := func(expected string) {
+		select {
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting on update with LogLevel %s", expected)
+		case update := <-updates:
+			require.IsType(t, &writableInfo{}, update)
+			assert.Equal(t, expected, update.(*writableInfo).LogLevel)
+		case err := <-errs:
+			t.Fatalf("received WatchForChanges error: %v", err)
+		}
+	}
+
+	expectUpdate("INFO")
+
+	// a change of a lower layer is hidden by the higher one
+	require.NoError(t, service.PutConfigurationValue("Writable/LogLevel", []byte("DEBUG")))
+	expectUpdate("DEBUG")
+
+	require.NoError(t, common.PutConfigurationValue("Writable/LogLevel", []byte("WARN")))
+	select {
+	case update := <-updates:
+		t.Fatalf("unexpected update %v", update)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	require.NoError(t, service.DeleteConfigurationValue("Writable/LogLevel"))
+	expectUpdate("WARN")
+}
+
+// failingWatchClient fails to set up its watches, reporting the error before returning
+type failingWatchClient struct {
+	Client
+}
+
+func (client failingWatchClient) WatchForChanges(_ chan<- interface{}, errorChannel chan<- error, _ interface{}, _ string, _ messaging.MessageClient) {
+	errorChannel <- errors.New("subscribe failed")
+}
+
+func TestLayeredClientWatchSetUpError(t *testing.T) {
+	common, service := makeLayers(t)
+	target := failingWatchClient{service}
+	client, err := NewLayeredClient(target, failingWatchClient{common}, target)
+	require.NoError(t, err)
+
+	// the updates and errors pending aren't read until the watch has been set up
+	updates := make(chan interface{})
+	errs := make(chan error)
+	returned := make(chan struct{})
+	go func() {
+		client.WatchForChanges(updates, errs, &writableInfo{}, "Writable", nil)
+		close(returned)
+	}()
+
+	select {
+	case <-returned:
+	case <-time.After(5 * time.Second):
+		t.Fatal("WatchForChanges did not return")
+	}
+
+	received := 0
+	for received < 2 {
+		select {
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting on set up errors")
+		case update := <-updates:
+			assert.Equal(t, "INFO", update.(*writableInfo).LogLevel)
+		case err := <-errs:
+			assert.EqualError(t, err, "subscribe failed")
+			received++
+		}
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		client.StopWatching()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("StopWatching did not return")
+	}
+}
@@ -0,0 +1,95 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package configuration
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/kvtree"
+
+	"github.com/edgexfoundry/go-mod-messaging/v3/messaging"
+)
+
+// mapDecoder decodes the configuration map located at prefix, as sent by a watch, into target
+type mapDecoder func(prefix string, raw interface{}, target interface{}) error
+
+// mapWatcher relays the updates of the watches of a wrapped Client, which decodes them into a configuration map,
+// after decoding them into the target configuration
+type mapWatcher struct {
+	client          Client
+	watchMutex      sync.Mutex
+	watchingDoneCtx context.Context
+	watchingDone    context.CancelFunc
+	watchingWait    sync.WaitGroup
+}
+
+func newMapWatcher(client Client) *mapWatcher {
+	watcher := mapWatcher{client: client}
+	watcher.watchingDoneCtx, watcher.watchingDone = context.WithCancel(context.Background())
+
+	return &watcher
+}
+
+// watch sets up a watch for the target key on the wrapped Client and sends back each of its updates on the update
+// channel once decoded by decode. Nil updates, which only signal that the watch has been set up, are sent as is.
+func (watcher *mapWatcher) watch(updateChannel chan<- interface{}, errorChannel chan<- error, configuration interface{}, waitKey string, msgClient messaging.MessageClient, decode mapDecoder) {
+	watcher.watchMutex.Lock()
+	defer watcher.watchMutex.Unlock()
+
+	updates := make(chan interface{})
+	watcher.client.WatchForChanges(updates, errorChannel, &map[string]any{}, waitKey, msgClient)
+
+	ctx := watcher.watchingDoneCtx
+	prefix := strings.Trim(waitKey, kvtree.KeyDelimiter)
+
+	watcher.watchingWait.Add(1)
+	go func() {
+		defer watcher.watchingWait.Done()
+
+		for {
+			var raw interface{}
+			select {
+			case <-ctx.Done():
+				return
+			case raw = <-updates:
+			}
+
+			if raw == nil {
+				if !send(ctx, updateChannel, nil) {
+					return
+				}
+				continue
+			}
+
+			if err := decode(prefix, raw, configuration); err != nil {
+				if !send(ctx, errorChannel, err) {
+					return
+				}
+				continue
+			}
+
+			if !send(ctx, updateChannel, configuration) {
+				return
+			}
+		}
+	}()
+}
+
+// stop causes all watches of the wrapped Client to stop and waits until they have stopped.
+func (watcher *mapWatcher) stop() {
+	watcher.watchMutex.Lock()
+	defer watcher.watchMutex.Unlock()
+
+	// the wrapped Client is stopped first, since it might be blocked on sending an update
+	watcher.client.StopWatching()
+
+	watcher.watchingDone()
+	watcher.watchingWait.Wait()
+
+	watcher.watchingDoneCtx, watcher.watchingDone = context.WithCancel(context.Background())
+}
@@ -36,6 +36,34 @@ func (_m *Client) ConfigurationValueExists(name string) (bool, error) {
 	return r0, r1
 }
 
+// DeleteConfigurationValue provides a mock function with given fields: name
+func (_m *Client) DeleteConfigurationValue(name string) error {
+	ret := _m.Called(name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteSubConfiguration provides a mock function with given fields: name
+func (_m *Client) DeleteSubConfiguration(name string) error {
+	ret := _m.Called(name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetConfiguration provides a mock function with given fields: configStruct
 func (_m *Client) GetConfiguration(configStruct interface{}) (interface{}, error) {
 	ret := _m.Called(configStruct)
@@ -0,0 +1,125 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package configuration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mitchellh/copystructure"
+
+	"github.com/edgexfoundry/go-mod-messaging/v3/messaging"
+)
+
+// GetConfigurationAs gets the full configuration from the Configuration service decoded into a new T
+func GetConfigurationAs[T any](client Client) (*T, error) {
+	raw, err := client.GetConfiguration(new(T))
+	if err != nil {
+		return nil, err
+	}
+
+	return toTyped[T](raw)
+}
+
+// WatchAs sets up a watch for the waitKey and sends each update of the configuration, decoded into a new T, on the
+// returned update channel. Errors of the watch, including those setting it up, are sent on the returned error channel.
+// Both channels are closed once either the context is done or the watch has exited. Updates without configuration,
+// such as the nil value sent by the Core Keeper watch once established, are skipped. The watch itself stops along with
+// the context for Clients implementing either ClientWithContext or WatcherClient. Other Clients can't stop a single
+// watch, so theirs is left blocked on sending its next update until StopWatching is called.
+func WatchAs[T any](ctx context.Context, client Client, waitKey string, msgClient messaging.MessageClient) (<-chan *T, <-chan error) {
+	rawUpdates := make(chan interface{})
+	rawErrors := make(chan error)
+	exited := make(chan error, 1)
+	updates := make(chan *T)
+	errs := make(chan error)
+
+	// the relay is started first, since a Client may send the error setting up the watch before returning
+	go func() {
+		relay(ctx, rawUpdates, rawErrors, exited, updates, errs)
+		close(updates)
+		close(errs)
+	}()
+
+	switch client := client.(type) {
+	case ClientWithContext:
+		client.WatchForChangesCtx(ctx, rawUpdates, rawErrors, new(T), waitKey, msgClient)
+	case WatcherClient:
+		watcher := client.Watch(ctx, rawUpdates, rawErrors, new(T), waitKey, msgClient)
+		go func() {
+			<-watcher.Done()
+			exited <- watcher.Err()
+		}()
+	default:
+		client.WatchForChanges(rawUpdates, rawErrors, new(T), waitKey, msgClient)
+	}
+
+	return updates, errs
+}
+
+// relay converts the updates of a watch to *T and sends them on, along with its errors, until either ctx is done or
+// the watch has exited. The reason the watch has exited is sent on unless it has been stopped.
+func relay[T any](ctx context.Context, rawUpdates <-chan interface{}, rawErrors <-chan error, exited <-chan error, updates chan<- *T, errs chan<- error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case err := <-exited:
+			if err != nil && !errors.Is(err, context.Canceled) {
+				send(ctx, errs, err)
+			}
+			return
+
+		case err := <-rawErrors:
+			if !send(ctx, errs, err) {
+				return
+			}
+
+		case raw := <-rawUpdates:
+			if raw == nil {
+				continue
+			}
+
+			update, err := toTyped[T](raw)
+			if err != nil {
+				if !send(ctx, errs, err) {
+					return
+				}
+				continue
+			}
+
+			if !send(ctx, updates, update) {
+				return
+			}
+		}
+	}
+}
+
+// toTyped converts the configuration returned by a Client to a *T. A deep copy is returned, since some Clients keep
+// on decoding into the same target for every update, reusing its maps and slices.
+func toTyped[T any](raw interface{}) (*T, error) {
+	switch value := raw.(type) {
+	case *T:
+		if value == nil {
+			return nil, fmt.Errorf("configuration of type %T is nil", raw)
+		}
+		copied, err := copystructure.Copy(value)
+		if err != nil {
+			return nil, fmt.Errorf("unable to copy configuration of type %T: %w", raw, err)
+		}
+		return copied.(*T), nil
+	case T:
+		copied, err := copystructure.Copy(&value)
+		if err != nil {
+			return nil, fmt.Errorf("unable to copy configuration of type %T: %w", raw, err)
+		}
+		return copied.(*T), nil
+	default:
+		return nil, fmt.Errorf("configuration of type %T doesn't match the expected type %T", raw, new(T))
+	}
+}
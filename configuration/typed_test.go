@@ -0,0 +1,293 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package configuration
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-configuration/v3/configuration/mocks"
+	"github.com/edgexfoundry/go-mod-configuration/v3/pkg/types"
+
+	"github.com/edgexfoundry/go-mod-messaging/v3/messaging"
+)
+
+type writableInfo struct {
+	LogLevel string
+}
+
+type typedConfig struct {
+	Writable writableInfo
+	Port     int
+}
+
+func TestGetConfigurationAs(t *testing.T) {
+	client, err := NewConfigurationClient(types.ServiceConfig{Type: MemoryType, BasePath: "typed"})
+	require.NoError(t, err)
+
+	require.NoError(t, client.PutConfiguration(typedConfig{Writable: writableInfo{LogLevel: "INFO"}, Port: 8000}, true))
+
+	actual, err := GetConfigurationAs[typedConfig](client)
+	require.NoError(t, err)
+	assert.Equal(t, &typedConfig{Writable: writableInfo{LogLevel: "INFO"}, Port: 8000}, actual)
+
+	t.Run("Value instead of pointer", func(t *testing.T) {
+		mockClient := &mocks.Client{}
+		mockClient.On("GetConfiguration", mock.Anything).Return(typedConfig{Port: 8000}, nil)
+
+		actual, err := GetConfigurationAs[typedConfig](mockClient)
+		require.NoError(t, err)
+		assert.Equal(t, &typedConfig{Port: 8000}, actual)
+	})
+
+	t.Run("Type mismatch", func(t *testing.T) {
+		mockClient := &mocks.Client{}
+		mockClient.On("GetConfiguration", mock.Anything).Return(&writableInfo{}, nil)
+
+		_, err := GetConfigurationAs[typedConfig](mockClient)
+		require.Error(t, err)
+	})
+}
+
+func TestWatchAs(t *testing.T) {
+	client, err := NewConfigurationClient(types.ServiceConfig{Type: MemoryType, BasePath: "typed"})
+	require.NoError(t, err)
+
+	defer client.StopWatching()
+
+	require.NoError(t, client.PutConfigurationValue("Writable/LogLevel", []byte("INFO")))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates, errs := WatchAs[writableInfo](ctx, client, "Writable", nil)
+
+	expectedLogLevels := []string{"INFO", "DEBUG"}
+	for _, expected := range expectedLogLevels {
+		select {
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting on update with LogLevel %s", expected)
+		case update := <-updates:
+			assert.Equal(t, expected, update.LogLevel)
+		case err := <-errs:
+			t.Fatalf("received WatchAs error: %v", err)
+		}
+
+		require.NoError(t, client.PutConfigurationValue("Writable/LogLevel", []byte("DEBUG")))
+	}
+
+	cancel()
+	_, open := <-updates
+	assert.False(t, open)
+}
+
+func TestWatchAsSkipsNilAndReportsMismatch(t *testing.T) {
+	mockClient := &mocks.Client{}
+	mockClient.On("WatchForChanges", mock.Anything, mock.Anything, mock.Anything, "Writable", nil).Run(func(args mock.Arguments) {
+		rawUpdates := args.Get(0).(chan<- interface{})
+		go func() {
+			rawUpdates <- nil
+			rawUpdates <- &typedConfig{}
+			rawUpdates <- &writableInfo{LogLevel: "INFO"}
+		}()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates, errs := WatchAs[writableInfo](ctx, mockClient, "Writable", nil)
+
+	select {
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting on type mismatch error")
+	case update := <-updates:
+		t.Fatalf("unexpected update %v", update)
+	case err := <-errs:
+		require.Error(t, err)
+	}
+
+	select {
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting on update")
+	case update := <-updates:
+		assert.Equal(t, &writableInfo{LogLevel: "INFO"}, update)
+	case err := <-errs:
+		t.Fatalf("received WatchAs error: %v", err)
+	}
+}
+
+func TestWatchAsUpdatesAreCopies(t *testing.T) {
+	type tableConfig struct {
+		Tables map[string]string
+		Hosts  []string
+	}
+
+	client, err := NewConfigurationClient(types.ServiceConfig{Type: MemoryType, BasePath: "typed"})
+	require.NoError(t, err)
+
+	defer client.StopWatching()
+
+	require.NoError(t, client.PutConfigurationValue("Service/Tables/Readings", []byte("readings")))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates, errs := WatchAs[tableConfig](ctx, client, "Service", nil)
+
+	receive := func() *tableConfig {
+		select {
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting on update")
+		case update := <-updates:
+			return update
+		case err := <-errs:
+			t.Fatalf("received WatchAs error: %v", err)
+		}
+		return nil
+	}
+
+	first := receive()
+	require.NoError(t, client.PutConfigurationValue("Service/Tables/Readings", []byte("events")))
+	second := receive()
+
+	// the update already handed out isn't modified by the next one
+	assert.Equal(t, map[string]string{"Readings": "readings"}, first.Tables)
+	assert.Equal(t, map[string]string{"Readings": "events"}, second.Tables)
+}
+
+// recordingClient records the handle of the last watch set up on the wrapped WatcherClient
+type recordingClient struct {
+	WatcherClient
+	watcher types.Watcher
+}
+
+func (client *recordingClient) Watch(ctx context.Context, updateChannel chan<- interface{}, errorChannel chan<- error, configuration interface{}, waitKey string, msgClient messaging.MessageClient) types.Watcher {
+	client.watcher = client.WatcherClient.Watch(ctx, updateChannel, errorChannel, configuration, waitKey, msgClient)
+	return client.watcher
+}
+
+func TestWatchAsCancelled(t *testing.T) {
+	memory, err := NewConfigurationClient(types.ServiceConfig{Type: MemoryType, BasePath: "typed"})
+	require.NoError(t, err)
+
+	defer memory.StopWatching()
+
+	require.NoError(t, memory.PutConfigurationValue("Writable/LogLevel", []byte("INFO")))
+
+	receiveFirst := func(updates <-chan *writableInfo, errs <-chan error) {
+		select {
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting on update")
+		case update := <-updates:
+			assert.Equal(t, "INFO", update.LogLevel)
+		case err := <-errs:
+			t.Fatalf("received WatchAs error: %v", err)
+		}
+	}
+	assertClosed := func(updates <-chan *writableInfo, errs <-chan error) {
+		_, open := <-updates
+		assert.False(t, open)
+		_, open = <-errs
+		assert.False(t, open)
+	}
+
+	t.Run("Watcher client", func(t *testing.T) {
+		client := &recordingClient{WatcherClient: memory.(WatcherClient)}
+		ctx, cancel := context.WithCancel(context.Background())
+		updates, errs := WatchAs[writableInfo](ctx, client, "Writable", nil)
+		receiveFirst(updates, errs)
+
+		cancel()
+		assertClosed(updates, errs)
+
+		// the watch itself is stopped along with the context
+		select {
+		case <-client.watcher.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting on the watch to stop")
+		}
+		assert.ErrorIs(t, client.watcher.Err(), context.Canceled)
+	})
+
+	t.Run("Client without cancellable watch", func(t *testing.T) {
+		client := struct{ Client }{memory}
+		ctx, cancel := context.WithCancel(context.Background())
+		updates, errs := WatchAs[writableInfo](ctx, client, "Writable", nil)
+		receiveFirst(updates, errs)
+
+		cancel()
+		assertClosed(updates, errs)
+
+		// the watch left blocked on sending its update stops along with all the others
+		require.NoError(t, memory.PutConfigurationValue("Writable/LogLevel", []byte("DEBUG")))
+		stopped := make(chan struct{})
+		go func() {
+			memory.StopWatching()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-time.After(5 * time.Second):
+			t.Fatal("StopWatching did not return")
+		}
+	})
+}
+
+func TestWatchAsSetUpError(t *testing.T) {
+	t.Run("Watcher client", func(t *testing.T) {
+		client, err := NewConfigurationClient(types.ServiceConfig{Type: FileType, Path: filepath.Join(t.TempDir(), "config.json")})
+		require.NoError(t, err)
+
+		defer client.StopWatching()
+
+		updates, errs := WatchAs[writableInfo](context.Background(), client, "../escape", nil)
+
+		select {
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting on set up error")
+		case update := <-updates:
+			t.Fatalf("unexpected update %v", update)
+		case err := <-errs:
+			require.Error(t, err)
+		}
+
+		// the watch has exited, so the channels are closed
+		_, open := <-updates
+		assert.False(t, open)
+	})
+
+	t.Run("Error sent before returning", func(t *testing.T) {
+		mockClient := &mocks.Client{}
+		mockClient.On("WatchForChanges", mock.Anything, mock.Anything, mock.Anything, "Writable", nil).Run(func(args mock.Arguments) {
+			args.Get(1).(chan<- error) <- errors.New("subscribe failed")
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		returned := make(chan struct{})
+		var errs <-chan error
+		go func() {
+			_, errs = WatchAs[writableInfo](ctx, mockClient, "Writable", nil)
+			close(returned)
+		}()
+
+		select {
+		case <-returned:
+		case <-time.After(5 * time.Second):
+			t.Fatal("WatchAs did not return")
+		}
+		assert.EqualError(t, <-errs, "subscribe failed")
+	})
+}
@@ -23,11 +23,17 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/kvtree"
+	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/retry"
+	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/tlsconfig"
+	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/token"
+	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/watch"
 	"github.com/edgexfoundry/go-mod-configuration/v3/pkg/types"
 
 	"github.com/edgexfoundry/go-mod-messaging/v3/messaging"
@@ -38,18 +44,32 @@ import (
 
 const (
 	consulStatusPath = "/v1/status/leader"
-	aclError         = "Unexpected response code: 403"
+	// consulTokenHeader is the header holding the Access Token of the requests
+	consulTokenHeader = "X-Consul-Token" // nolint: gosec
+	aclError          = "Unexpected response code: 403"
+	// aclDeniedError is the body of the 403 response, which is all the transaction API reports on an auth error
+	aclDeniedError = "Permission denied"
+	// maxTxnOps is the maximum number of operations Consul accepts in a single transaction
+	maxTxnOps = 64
+	// watchWaitTime is the maximum time a blocking query of WatchForEvents waits for a change
+	watchWaitTime = 5 * time.Minute
 )
 
+// consulClient is safe for concurrent use, an Access Token rejected by simultaneous requests is renewed once
 type consulClient struct {
-	consulUrl       string
-	consulClient    *consulapi.Client
-	consulConfig    *consulapi.Config
-	configBasePath  string
+	consulUrl      string
+	consulClient   *consulapi.Client
+	consulConfig   *consulapi.Config
+	configBasePath string
+	// watchMutex guards watchingDoneCtx, which is renewed once StopWatching has stopped all watches
+	watchMutex      sync.Mutex
 	watchingDoneCtx context.Context
 	watchingDone    context.CancelFunc
 	watchingWait    sync.WaitGroup
-	getAccessToken  types.GetAccessTokenCallback
+	tokenProvider   *token.Provider
+	timeouts        types.TimeoutInfo
+	retryPolicy     types.RetryInfo
+	debounceWindow  time.Duration
 }
 
 // NewConsulClient creates a new Consul Client. Service details are optional, not needed just for configuration, but required if registering
@@ -58,7 +78,9 @@ func NewConsulClient(config types.ServiceConfig) (*consulClient, error) {
 	client := consulClient{
 		consulUrl:      config.GetUrl(),
 		configBasePath: config.BasePath,
-		getAccessToken: config.GetAccessToken,
+		timeouts:       config.Timeouts,
+		retryPolicy:    config.Retry,
+		debounceWindow: config.DebounceWindow,
 	}
 
 	client.watchingDoneCtx, client.watchingDone = context.WithCancel(context.Background())
@@ -69,11 +91,38 @@ func NewConsulClient(config types.ServiceConfig) (*consulClient, error) {
 
 	var err error
 
+	client.tokenProvider, err = token.NewProvider(config)
+	if err != nil {
+		return nil, err
+	}
+
 	client.consulConfig = consulapi.DefaultConfig()
-	client.consulConfig.Token = config.AccessToken
 	client.consulConfig.Address = client.consulUrl
+
+	// the Access Token is set on each request, rather than in the config, so that it is swapped once renewed
+	// without recreating the client
+	client.consulConfig.Token = ""
+	var transport http.RoundTripper
+	if config.TLS.IsEnabled() {
+		transport, err = tlsconfig.NewTransport(config.TLS)
+	} else {
+		var httpClient *http.Client
+		httpClient, err = consulapi.NewHttpClient(client.consulConfig.Transport, client.consulConfig.TLSConfig)
+		if httpClient != nil {
+			transport = httpClient.Transport
+		}
+	}
+	if err != nil {
+		client.tokenProvider.Stop()
+		return nil, fmt.Errorf("unable for create new Consul Client for %s: %v", client.consulUrl, err)
+	}
+	client.consulConfig.HttpClient = &http.Client{
+		Transport: &token.Transport{Base: transport, Provider: client.tokenProvider, Header: consulTokenHeader},
+	}
+
 	err = client.createConsulClient()
 	if err != nil {
+		client.tokenProvider.Stop()
 		return nil, err
 	}
 
@@ -92,13 +141,26 @@ func (client *consulClient) createConsulClient() error {
 
 // IsAlive simply checks if Consul is up and running at the configured URL
 func (client *consulClient) IsAlive() bool {
-	netClient := http.Client{Timeout: time.Second * 10}
+	return client.IsAliveCtx(context.Background())
+}
+
+// IsAliveCtx simply checks if Consul is up and running at the configured URL
+func (client *consulClient) IsAliveCtx(ctx context.Context) bool {
+	// the client of the Consul API is shared to check over the same TLS configuration
+	netClient := *client.consulConfig.HttpClient
+	netClient.Timeout = client.timeouts.GetLiveness()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, client.consulUrl+consulStatusPath, nil)
+	if err != nil {
+		return false
+	}
 
 	// This REST endpoint doesn't require Access Token, so no need to handle Auth Error.
-	resp, err := netClient.Get(client.consulUrl + consulStatusPath)
+	resp, err := netClient.Do(req)
 	if err != nil {
 		return false
 	}
+	_ = resp.Body.Close()
 
 	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
 		return true
@@ -109,15 +171,19 @@ func (client *consulClient) IsAlive() bool {
 
 // HasConfiguration checks to see if Consul contains the service's configuration.
 func (client *consulClient) HasConfiguration() (bool, error) {
-	stemKeys, _, err := client.consulClient.KV().Keys(client.configBasePath, "", nil)
-	retry, err := client.reloadAccessTokenOnAuthError(err)
-	if retry {
-		// Try again with new Access Token
-		stemKeys, _, err = client.consulClient.KV().Keys(client.configBasePath, "", nil)
-	}
+	return client.HasConfigurationCtx(context.Background())
+}
+
+// HasConfigurationCtx checks to see if Consul contains the service's configuration.
+func (client *consulClient) HasConfigurationCtx(ctx context.Context) (bool, error) {
+	var stemKeys []string
+	err := client.execute(ctx, client.timeouts.GetRead(), func(ctx context.Context) (err error) {
+		stemKeys, _, err = client.consulClient.KV().Keys(client.configBasePath, "", queryOptions(ctx))
+		return err
+	})
 
 	if err != nil {
-		return false, fmt.Errorf("checking configuration existence from Consul failed: %v", err)
+		return false, fmt.Errorf("checking configuration existence from Consul failed: %w", err)
 	} else if len(stemKeys) == 0 {
 		return false, nil
 	}
@@ -127,15 +193,19 @@ func (client *consulClient) HasConfiguration() (bool, error) {
 
 // HasSubConfiguration checks to see if the Configuration service contains the service's sub configuration.
 func (client *consulClient) HasSubConfiguration(name string) (bool, error) {
-	stemKeys, _, err := client.consulClient.KV().Keys(client.fullPath(name), "", nil)
-	retry, err := client.reloadAccessTokenOnAuthError(err)
-	if retry {
-		// Try again with new Access Token
-		stemKeys, _, err = client.consulClient.KV().Keys(client.fullPath(name), "", nil)
-	}
+	return client.HasSubConfigurationCtx(context.Background(), name)
+}
+
+// HasSubConfigurationCtx checks to see if the Configuration service contains the service's sub configuration.
+func (client *consulClient) HasSubConfigurationCtx(ctx context.Context, name string) (bool, error) {
+	var stemKeys []string
+	err := client.execute(ctx, client.timeouts.GetRead(), func(ctx context.Context) (err error) {
+		stemKeys, _, err = client.consulClient.KV().Keys(client.fullPath(name), "", queryOptions(ctx))
+		return err
+	})
 
 	if err != nil {
-		return false, fmt.Errorf("checking sub configuration existence from Consul failed: %v", err)
+		return false, fmt.Errorf("checking sub configuration existence from Consul failed: %w", err)
 	} else if len(stemKeys) == 0 {
 		return false, nil
 	}
@@ -146,52 +216,144 @@ func (client *consulClient) HasSubConfiguration(name string) (bool, error) {
 // PutConfigurationMap puts a full configuration map into Consul.
 // The sub-paths to where the values are to be stored in Consul are generated from the map key.
 func (client *consulClient) PutConfigurationMap(configuration map[string]any, overwrite bool) error {
+	return client.PutConfigurationMapCtx(context.Background(), configuration, overwrite)
+}
+
+// PutConfigurationMapCtx puts a full configuration map into Consul.
+// The sub-paths to where the values are to be stored in Consul are generated from the map key.
+func (client *consulClient) PutConfigurationMapCtx(ctx context.Context, configuration map[string]any, overwrite bool) error {
+	_, err := client.PutConfigurationMapBatch(ctx, configuration, overwrite)
+	return err
+}
+
+// PutConfigurationMapBatch puts a full configuration map into Consul using transactions of up to maxTxnOps keys,
+// and reports which keys have been written, skipped or failed. Without overwrite, the existing keys are skipped
+// and the remaining ones are only created if they still don't exist when the transaction is applied. Such transactions
+// are made once, as they may have been applied despite a transient failure.
+func (client *consulClient) PutConfigurationMapBatch(ctx context.Context, configuration map[string]any, overwrite bool) (types.PutResult, error) {
+	var result types.PutResult
 
 	keyValues := convertInterfaceToConsulPairs("", configuration)
+	sort.Slice(keyValues, func(i, j int) bool { return keyValues[i].Key < keyValues[j].Key })
+
+	if !overwrite {
+		var existingKeys []string
+		err := client.execute(ctx, client.timeouts.GetRead(), func(ctx context.Context) (err error) {
+			existingKeys, _, err = client.consulClient.KV().Keys(client.configBasePath, "", queryOptions(ctx))
+			return err
+		})
+		if err != nil {
+			result.Failed = pairKeys(keyValues)
+			return result, fmt.Errorf("unable to get existing keys for %s from Consul: %w", client.configBasePath, err)
+		}
+
+		existing := make(map[string]bool, len(existingKeys))
+		for _, key := range existingKeys {
+			existing[key] = true
+		}
+
+		var missing []*pair
+		for _, keyValue := range keyValues {
+			if existing[client.fullPath(keyValue.Key)] {
+				result.Skipped = append(result.Skipped, keyValue.Key)
+			} else {
+				missing = append(missing, keyValue)
+			}
+		}
+		keyValues = missing
+	}
+
+	for start := 0; start < len(keyValues); start += maxTxnOps {
+		chunk := keyValues[start:min(start+maxTxnOps, len(keyValues))]
+
+		ops := make(consulapi.TxnOps, 0, len(chunk))
+		for _, keyValue := range chunk {
+			op := &consulapi.KVTxnOp{
+				Verb:  consulapi.KVSet,
+				Key:   client.fullPath(keyValue.Key),
+				Value: []byte(keyValue.Value),
+			}
+			if !overwrite {
+				// Check-and-set with index 0 only creates the key if it doesn't exist
+				op.Verb = consulapi.KVCAS
+			}
+			ops = append(ops, &consulapi.TxnOp{KV: op})
+		}
 
-	// Put config properties into Consul.
-	for _, keyValue := range keyValues {
-		exists, _ := client.ConfigurationValueExists(keyValue.Key)
-		if !exists || overwrite {
-			if err := client.PutConfigurationValue(keyValue.Key, []byte(keyValue.Value)); err != nil {
+		txn := func(ctx context.Context) error {
+			ok, response, _, err := client.consulClient.Txn().Txn(ops, queryOptions(ctx))
+			if err != nil {
 				return err
 			}
+			if !ok {
+				return txnError(response)
+			}
+			return nil
+		}
+
+		var err error
+		if overwrite {
+			err = client.execute(ctx, client.timeouts.GetWrite(), txn)
+		} else {
+			err = client.executeOnce(ctx, client.timeouts.GetWrite(), txn)
+		}
+		if err != nil {
+			result.Failed = pairKeys(keyValues[start:])
+			return result, fmt.Errorf("unable to put configuration into Consul: %w", err)
 		}
+
+		result.Written = append(result.Written, pairKeys(chunk)...)
 	}
 
-	return nil
+	return result, nil
 }
 
 // PutConfiguration puts a full configuration struct into the Configuration provider
 func (client *consulClient) PutConfiguration(configuration interface{}, overwrite bool) error {
+	return client.PutConfigurationCtx(context.Background(), configuration, overwrite)
+}
+
+// PutConfigurationCtx puts a full configuration struct into the Configuration provider
+func (client *consulClient) PutConfigurationCtx(ctx context.Context, configuration interface{}, overwrite bool) error {
+	_, err := client.PutConfigurationBatch(ctx, configuration, overwrite)
+	return err
+}
+
+// PutConfigurationBatch puts a full configuration struct into Consul and reports which keys have been written,
+// skipped or failed.
+func (client *consulClient) PutConfigurationBatch(ctx context.Context, configuration interface{}, overwrite bool) (types.PutResult, error) {
 	configMap := make(map[string]any)
 	bytes, err := json.Marshal(configuration)
 	if err != nil {
-		return err
+		return types.PutResult{}, err
 	}
 
 	err = json.Unmarshal(bytes, &configMap)
 	if err != nil {
-		return err
-	}
-
-	err = client.PutConfigurationMap(configMap, overwrite)
-	if err != nil {
-		return err
+		return types.PutResult{}, err
 	}
 
-	return nil
-
+	return client.PutConfigurationMapBatch(ctx, configMap, overwrite)
 }
 
 // GetConfiguration gets the full configuration from Consul into the target configuration struct.
 // Passed in struct is only a reference for decoder, empty struct is ok
 // Returns the configuration in the target struct as interface{}, which caller must cast
 func (client *consulClient) GetConfiguration(configStruct interface{}) (interface{}, error) {
+	return client.GetConfigurationCtx(context.Background(), configStruct)
+}
+
+// GetConfigurationCtx gets the full configuration from Consul into the target configuration struct.
+// Passed in struct is only a reference for decoder, empty struct is ok
+// Returns the configuration in the target struct as interface{}, which caller must cast
+func (client *consulClient) GetConfigurationCtx(ctx context.Context, configStruct interface{}) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(ctx, client.timeouts.GetLoad())
+	defer cancel()
+
 	var err error
 	var configuration interface{}
 
-	exists, err := client.HasConfiguration()
+	exists, err := client.HasConfigurationCtx(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -205,7 +367,6 @@ func (client *consulClient) GetConfiguration(configStruct interface{}) (interfac
 	errorChannel := make(chan error)
 
 	decoder := client.newConsulDecoder()
-	decoder.Consul = client.consulConfig
 	decoder.Target = configStruct
 	decoder.Prefix = client.configBasePath
 	decoder.ErrCh = errorChannel
@@ -220,8 +381,12 @@ func (client *consulClient) GetConfiguration(configStruct interface{}) (interfac
 	go decoder.Run()
 
 	select {
-	case <-time.After(2 * time.Second):
-		err = errors.New("timeout loading config from client")
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("timeout loading config from client after %v", client.timeouts.GetLoad())
+		} else {
+			err = fmt.Errorf("loading config from client canceled: %w", ctx.Err())
+		}
 	case ex := <-errorChannel:
 		err = errors.New(ex.Error())
 	case raw := <-updateChannel:
@@ -234,7 +399,29 @@ func (client *consulClient) GetConfiguration(configStruct interface{}) (interfac
 // WatchForChanges sets up a Consul watch for the target key and send back updates on the update channel.
 // Passed in struct is only a reference for decoder, empty struct is ok
 // Sends the configuration in the target struct as interface{} on updateChannel, which caller must cast
-func (client *consulClient) WatchForChanges(updateChannel chan<- interface{}, errorChannel chan<- error, configuration interface{}, watchKey string, _ messaging.MessageClient) {
+func (client *consulClient) WatchForChanges(updateChannel chan<- interface{}, errorChannel chan<- error, configuration interface{}, watchKey string, msgClient messaging.MessageClient) {
+	client.WatchForChangesCtx(context.Background(), updateChannel, errorChannel, configuration, watchKey, msgClient)
+}
+
+// WatchForChangesCtx sets up a Consul watch for the target key and send back updates on the update channel.
+// The watch stops when either the context is done or StopWatching is called.
+// Passed in struct is only a reference for decoder, empty struct is ok
+// Sends the configuration in the target struct as interface{} on updateChannel, which caller must cast
+func (client *consulClient) WatchForChangesCtx(ctx context.Context, updateChannel chan<- interface{}, errorChannel chan<- error, configuration interface{}, watchKey string, _ messaging.MessageClient) {
+	client.watchForChanges(ctx, updateChannel, errorChannel, configuration, watchKey, func(error) {})
+}
+
+// Watch sets up a Consul watch for the target key like WatchForChangesCtx and returns the handle to stop it,
+// independently of the other watches of the client.
+func (client *consulClient) Watch(ctx context.Context, updateChannel chan<- interface{}, errorChannel chan<- error, configuration interface{}, watchKey string, _ messaging.MessageClient) types.Watcher {
+	watcher, ctx := watch.New(ctx)
+	client.watchForChanges(ctx, updateChannel, errorChannel, configuration, watchKey, watcher.Finish)
+	return watcher
+}
+
+// watchForChanges runs the Consul decoder for the target key until either ctx is done or StopWatching is called,
+// and calls exited with the reason once it has stopped.
+func (client *consulClient) watchForChanges(ctx context.Context, updateChannel chan<- interface{}, errorChannel chan<- error, configuration interface{}, watchKey string, exited func(error)) {
 	// some watch keys may have start with "/", need to remove it since the base path already has it.
 	if strings.Index(watchKey, "/") == 0 {
 		watchKey = watchKey[1:]
@@ -242,72 +429,205 @@ func (client *consulClient) WatchForChanges(updateChannel chan<- interface{}, er
 
 	errs := make(chan error)
 	decoder := client.newConsulDecoder()
-	decoder.Consul = client.consulConfig
 	decoder.Target = configuration
 	decoder.Prefix = client.configBasePath + watchKey
 	decoder.ErrCh = errs
 	decoder.UpdateCh = updateChannel
+	// the changes are coalesced until they have quiesced for the debounce window,
+	// which is the decoder's default of 500ms if not set
+	if client.debounceWindow > 0 {
+		decoder.QuiescencePeriod = client.debounceWindow
+		decoder.QuiescenceTimeout = watch.MaxDelay(client.debounceWindow)
+	}
 
+	ctx, watchDone := client.startWatch(ctx)
+
+	// the Access Token the decoder has been started with, which its rejected requests were made with
+	token := client.tokenProvider.Token()
 	go decoder.Run()
-	client.watchingWait.Add(1)
 
 	go func() {
+		defer watchDone()
+
 		for {
 			select {
-			case <-client.watchingDoneCtx.Done():
+			case <-ctx.Done():
 				_ = decoder.Close() // Func always return nil for error so ignoring the return value
-				client.watchingWait.Done()
+				exited(ctx.Err())
 				return
 
 			case err := <-errs:
-				retry, err := client.reloadAccessTokenOnAuthError(err)
+				retry, err := client.reloadAccessTokenOnAuthError(err, token)
 				if retry {
 					_ = decoder.Close() // Func always return nil for error so ignoring the return value
-					decoder.Consul = client.consulConfig
+					decoder.Consul = client.newConsulConfig()
+					token = client.tokenProvider.Token()
 					go decoder.Run()
 				} else {
-					errorChannel <- err
+					select {
+					case errorChannel <- err:
+					case <-ctx.Done():
+					}
 				}
 			}
 		}
 	}()
 }
 
+// WatchForEvents sets up a blocking query on the keys under watchKey and sends a ChangeEvent for every key which has
+// been added, modified or deleted since the previous result. The Index of the events is the ModifyIndex of the key,
+// or the index of the query result for deleted keys.
+func (client *consulClient) WatchForEvents(ctx context.Context, eventChannel chan<- types.ChangeEvent, errorChannel chan<- error, watchKey string, _ messaging.MessageClient) {
+	// some watch keys may have start with "/", need to remove it since the base path already has it.
+	watchKey = strings.TrimPrefix(watchKey, "/")
+	prefix := client.configBasePath + watchKey
+
+	ctx, watchDone := client.startWatch(ctx)
+
+	go func() {
+		defer watchDone()
+
+		var snapshot map[string]string
+		var waitIndex uint64
+		failures := 0
+
+		for {
+			options := &consulapi.QueryOptions{WaitIndex: waitIndex, WaitTime: watchWaitTime}
+			token := client.tokenProvider.Token()
+			pairs, meta, err := client.consulClient.KV().List(prefix, options.WithContext(ctx))
+			if ctx.Err() != nil {
+				return
+			}
+
+			if err != nil {
+				if renewed, _ := client.reloadAccessTokenOnAuthError(err, token); renewed {
+					continue
+				}
+
+				failures++
+				select {
+				case errorChannel <- fmt.Errorf("unable to watch %s in Consul: %w", prefix, err):
+				case <-ctx.Done():
+					return
+				}
+				if !sleep(ctx, retry.Backoff(client.retryPolicy, failures)) {
+					return
+				}
+				continue
+			}
+			failures = 0
+
+			current := make(map[string]string, len(pairs))
+			indexes := make(map[string]uint64, len(pairs))
+			for _, keyPair := range pairs {
+				key := strings.TrimPrefix(keyPair.Key, client.configBasePath)
+				current[key] = string(keyPair.Value)
+				indexes[key] = keyPair.ModifyIndex
+			}
+
+			// The first result is the baseline the following changes are compared to
+			if snapshot != nil {
+				for _, event := range kvtree.Diff(snapshot, current) {
+					event.Index = meta.LastIndex
+					if index, exists := indexes[event.Key]; exists {
+						event.Index = index
+					}
+
+					select {
+					case eventChannel <- event:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+			snapshot = current
+
+			// Reset the index if it went backwards, i.e. after a Consul restart
+			if meta.LastIndex < waitIndex {
+				waitIndex = 0
+			} else {
+				waitIndex = meta.LastIndex
+			}
+
+			// A query without index, i.e. for a prefix which doesn't exist yet, returns immediately
+			if meta.LastIndex == 0 && !sleep(ctx, client.retryPolicy.GetInitialBackoff()) {
+				return
+			}
+		}
+	}()
+}
+
 // StopWatching causes all WatchForChanges processing to stop and waits until they have exited.
 func (client *consulClient) StopWatching() {
+	client.watchMutex.Lock()
+	defer client.watchMutex.Unlock()
+
 	client.watchingDone()
 	client.watchingWait.Wait()
+
+	client.watchingDoneCtx, client.watchingDone = context.WithCancel(context.Background())
+}
+
+// Close stops all watches along with the background renewal of the Access Token. The client must not be used
+// afterward.
+func (client *consulClient) Close() error {
+	client.StopWatching()
+	client.tokenProvider.Stop()
+	return nil
+}
+
+// startWatch tracks a new watch, which is to stop once either ctx is done or StopWatching is called.
+// The returned function must be called once the watch has exited.
+func (client *consulClient) startWatch(ctx context.Context) (context.Context, func()) {
+	client.watchMutex.Lock()
+	defer client.watchMutex.Unlock()
+
+	client.watchingWait.Add(1)
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(client.watchingDoneCtx, cancel)
+
+	return ctx, func() {
+		stop()
+		cancel()
+		client.watchingWait.Done()
+	}
 }
 
 // ConfigurationValueExists checks if a configuration value exists in Consul
 func (client *consulClient) ConfigurationValueExists(name string) (bool, error) {
-	keyPair, _, err := client.consulClient.KV().Get(client.fullPath(name), nil)
+	return client.ConfigurationValueExistsCtx(context.Background(), name)
+}
 
-	retry, err := client.reloadAccessTokenOnAuthError(err)
-	if retry {
-		// Try again with new Access Token
-		keyPair, _, err = client.consulClient.KV().Get(client.fullPath(name), nil)
-	}
+// ConfigurationValueExistsCtx checks if a configuration value exists in Consul
+func (client *consulClient) ConfigurationValueExistsCtx(ctx context.Context, name string) (bool, error) {
+	var keyPair *consulapi.KVPair
+	err := client.execute(ctx, client.timeouts.GetRead(), func(ctx context.Context) (err error) {
+		keyPair, _, err = client.consulClient.KV().Get(client.fullPath(name), queryOptions(ctx))
+		return err
+	})
 
 	if err != nil {
-		return false, fmt.Errorf("unable to check existence of %s in Consul: %v", client.fullPath(name), err)
+		return false, fmt.Errorf("unable to check existence of %s in Consul: %w", client.fullPath(name), err)
 	}
 
 	return keyPair != nil, nil
 }
 
 // GetConfigurationValue gets a specific configuration value from Consul
-func (client *consulClient) GetConfigurationValue(fullPath string) ([]byte, error) {
-	keyPair, _, err := client.consulClient.KV().Get(client.fullPath(fullPath), nil)
+func (client *consulClient) GetConfigurationValue(name string) ([]byte, error) {
+	return client.GetConfigurationValueCtx(context.Background(), name)
+}
 
-	retry, err := client.reloadAccessTokenOnAuthError(err)
-	if retry {
-		// Try again with new Access Token
-		keyPair, _, err = client.consulClient.KV().Get(client.fullPath(fullPath), nil)
-	}
+// GetConfigurationValueCtx gets a specific configuration value from Consul
+func (client *consulClient) GetConfigurationValueCtx(ctx context.Context, name string) ([]byte, error) {
+	var keyPair *consulapi.KVPair
+	err := client.execute(ctx, client.timeouts.GetRead(), func(ctx context.Context) (err error) {
+		keyPair, _, err = client.consulClient.KV().Get(client.fullPath(name), queryOptions(ctx))
+		return err
+	})
 
 	if err != nil {
-		return nil, fmt.Errorf("unable to get value for %s from Consul: %v", client.fullPath(fullPath), err)
+		return nil, fmt.Errorf("unable to get value for %s from Consul: %w", client.fullPath(name), err)
 	}
 
 	if keyPair == nil {
@@ -318,17 +638,20 @@ func (client *consulClient) GetConfigurationValue(fullPath string) ([]byte, erro
 }
 
 // GetConfigurationValueByFullPath gets a specific configuration value given the full path from Consul
-func (client *consulClient) GetConfigurationValueByFullPath(name string) ([]byte, error) {
-	keyPair, _, err := client.consulClient.KV().Get(name, nil)
+func (client *consulClient) GetConfigurationValueByFullPath(fullPath string) ([]byte, error) {
+	return client.GetConfigurationValueByFullPathCtx(context.Background(), fullPath)
+}
 
-	retry, err := client.reloadAccessTokenOnAuthError(err)
-	if retry {
-		// Try again with new Access Token
-		keyPair, _, err = client.consulClient.KV().Get(name, nil)
-	}
+// GetConfigurationValueByFullPathCtx gets a specific configuration value given the full path from Consul
+func (client *consulClient) GetConfigurationValueByFullPathCtx(ctx context.Context, fullPath string) ([]byte, error) {
+	var keyPair *consulapi.KVPair
+	err := client.execute(ctx, client.timeouts.GetRead(), func(ctx context.Context) (err error) {
+		keyPair, _, err = client.consulClient.KV().Get(fullPath, queryOptions(ctx))
+		return err
+	})
 
 	if err != nil {
-		return nil, fmt.Errorf("unable to get value for %s from Consul: %v", name, err)
+		return nil, fmt.Errorf("unable to get value for %s from Consul: %w", fullPath, err)
 	}
 
 	if keyPair == nil {
@@ -340,37 +663,100 @@ func (client *consulClient) GetConfigurationValueByFullPath(name string) ([]byte
 
 // PutConfigurationValue puts a specific configuration value into Consul
 func (client *consulClient) PutConfigurationValue(name string, value []byte) error {
+	return client.PutConfigurationValueCtx(context.Background(), name, value)
+}
+
+// PutConfigurationValueCtx puts a specific configuration value into Consul
+func (client *consulClient) PutConfigurationValueCtx(ctx context.Context, name string, value []byte) error {
 	keyPair := &consulapi.KVPair{
 		Key:   client.fullPath(name),
 		Value: value,
 	}
 
-	_, err := client.consulClient.KV().Put(keyPair, nil)
+	err := client.execute(ctx, client.timeouts.GetWrite(), func(ctx context.Context) (err error) {
+		_, err = client.consulClient.KV().Put(keyPair, writeOptions(ctx))
+		return err
+	})
 
-	retry, err := client.reloadAccessTokenOnAuthError(err)
-	if retry {
-		// Try again with new Access Token
-		_, err = client.consulClient.KV().Put(keyPair, nil)
+	if err != nil {
+		return fmt.Errorf("unable to put value for %s into Consul: %w", client.fullPath(name), err)
 	}
 
+	return nil
+}
+
+// GetConfigurationValueWithVersion gets a specific configuration value from Consul along with its ModifyIndex
+func (client *consulClient) GetConfigurationValueWithVersion(name string) ([]byte, uint64, error) {
+	return client.GetConfigurationValueWithVersionCtx(context.Background(), name)
+}
+
+// GetConfigurationValueWithVersionCtx gets a specific configuration value from Consul along with its ModifyIndex
+func (client *consulClient) GetConfigurationValueWithVersionCtx(ctx context.Context, name string) ([]byte, uint64, error) {
+	var keyPair *consulapi.KVPair
+	err := client.execute(ctx, client.timeouts.GetRead(), func(ctx context.Context) (err error) {
+		keyPair, _, err = client.consulClient.KV().Get(client.fullPath(name), queryOptions(ctx))
+		return err
+	})
+
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to get value for %s from Consul: %w", client.fullPath(name), err)
+	}
+
+	if keyPair == nil {
+		return nil, 0, nil
+	}
+
+	return keyPair.Value, keyPair.ModifyIndex, nil
+}
+
+// PutConfigurationValueCAS puts a specific configuration value into Consul only if its ModifyIndex still is expectedVersion
+func (client *consulClient) PutConfigurationValueCAS(name string, value []byte, expectedVersion uint64) error {
+	return client.PutConfigurationValueCASCtx(context.Background(), name, value, expectedVersion)
+}
+
+// PutConfigurationValueCASCtx puts a specific configuration value into Consul only if its ModifyIndex still is expectedVersion.
+// The write is made once, a transient failure is returned as the value may have been written despite it.
+func (client *consulClient) PutConfigurationValueCASCtx(ctx context.Context, name string, value []byte, expectedVersion uint64) error {
+	keyPair := &consulapi.KVPair{
+		Key:         client.fullPath(name),
+		Value:       value,
+		ModifyIndex: expectedVersion,
+	}
+
+	var swapped bool
+	err := client.executeOnce(ctx, client.timeouts.GetWrite(), func(ctx context.Context) (err error) {
+		swapped, _, err = client.consulClient.KV().CAS(keyPair, writeOptions(ctx))
+		return err
+	})
+
 	if err != nil {
-		return fmt.Errorf("unable to put value for %s into Consul: %v", client.fullPath(name), err)
+		return fmt.Errorf("unable to put value for %s into Consul: %w", client.fullPath(name), err)
+	}
+
+	if !swapped {
+		// The actual version is only informational, so failing to get it doesn't hide the conflict
+		_, actualVersion, _ := client.GetConfigurationValueWithVersionCtx(ctx, name)
+		return &types.ConflictError{Key: client.fullPath(name), ExpectedVersion: expectedVersion, ActualVersion: actualVersion}
 	}
 
 	return nil
 }
 
+// GetConfigurationKeys returns all keys under name
 func (client *consulClient) GetConfigurationKeys(name string) ([]string, error) {
-	keyPairs, _, err := client.consulClient.KV().List(client.fullPath(name), nil)
+	return client.GetConfigurationKeysCtx(context.Background(), name)
+}
 
-	retry, err := client.reloadAccessTokenOnAuthError(err)
-	if retry {
-		// Try again with new Access Token
-		keyPairs, _, err = client.consulClient.KV().List(client.fullPath(name), nil)
-	}
+// GetConfigurationKeysCtx returns all keys under name
+func (client *consulClient) GetConfigurationKeysCtx(ctx context.Context, name string) ([]string, error) {
+	var keyPairs consulapi.KVPairs
+	err := client.execute(ctx, client.timeouts.GetRead(), func(ctx context.Context) (err error) {
+		keyPairs, _, err = client.consulClient.KV().List(client.fullPath(name), queryOptions(ctx))
+		return err
+	})
 
 	if err != nil {
-		return nil, fmt.Errorf("unable to get list of keys for %s from Consul: %v", client.fullPath(name), err)
+		return nil, fmt.Errorf("unable to get list of keys for %s from Consul: %w", client.fullPath(name), err)
 	}
 
 	if keyPairs == nil {
@@ -384,23 +770,98 @@ func (client *consulClient) GetConfigurationKeys(name string) ([]string, error)
 	return list, nil
 }
 
-func (client *consulClient) reloadAccessTokenOnAuthError(err error) (bool, error) {
-	if err == nil {
-		return false, nil
+// DeleteConfigurationValue removes a specific configuration value from Consul
+func (client *consulClient) DeleteConfigurationValue(name string) error {
+	return client.DeleteConfigurationValueCtx(context.Background(), name)
+}
+
+// DeleteConfigurationValueCtx removes a specific configuration value from Consul
+func (client *consulClient) DeleteConfigurationValueCtx(ctx context.Context, name string) error {
+	err := client.execute(ctx, client.timeouts.GetWrite(), func(ctx context.Context) (err error) {
+		_, err = client.consulClient.KV().Delete(client.fullPath(name), writeOptions(ctx))
+		return err
+	})
+
+	if err != nil {
+		return fmt.Errorf("unable to delete value for %s from Consul: %w", client.fullPath(name), err)
 	}
 
-	if strings.Contains(err.Error(), aclError) && client.getAccessToken != nil {
-		newToken, err := client.getAccessToken()
-		if err != nil {
-			err = fmt.Errorf("failed to renew access token: %s", err.Error())
-			return false, err
-		}
+	return nil
+}
 
-		client.consulConfig.Token = newToken
+// DeleteSubConfiguration removes all configuration values located under name from Consul
+func (client *consulClient) DeleteSubConfiguration(name string) error {
+	return client.DeleteSubConfigurationCtx(context.Background(), name)
+}
 
-		// Have to recreate the consul client with the new Access Token
-		err = client.createConsulClient()
-		if err != nil {
+// DeleteSubConfigurationCtx removes all configuration values located under name from Consul
+func (client *consulClient) DeleteSubConfigurationCtx(ctx context.Context, name string) error {
+	// The trailing slash keeps sibling keys sharing the same prefix, i.e. Writable vs WritableExtra, from being deleted
+	prefix := client.fullPath(name)
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	err := client.execute(ctx, client.timeouts.GetWrite(), func(ctx context.Context) (err error) {
+		_, err = client.consulClient.KV().DeleteTree(prefix, writeOptions(ctx))
+		return err
+	})
+
+	if err != nil {
+		return fmt.Errorf("unable to delete sub configuration %s from Consul: %w", prefix, err)
+	}
+
+	return nil
+}
+
+// execute makes the request with the timeout applied to each attempt. The request is made again once with a renewed
+// Access Token on an auth error, and retried according to the retry policy on transient failures.
+func (client *consulClient) execute(ctx context.Context, timeout time.Duration, request func(ctx context.Context) error) error {
+	return retry.Do(ctx, client.retryPolicy, isRetryable, func(ctx context.Context) error {
+		return client.executeOnce(ctx, timeout, request)
+	})
+}
+
+// executeOnce makes the request with the timeout applied, only making it again once with a renewed Access Token on an
+// auth error, as a rejected request hasn't been applied. It is used for conditional writes, which aren't retried on
+// transient failures: they may have been applied despite the failure, and would then be reported as a conflict.
+func (client *consulClient) executeOnce(ctx context.Context, timeout time.Duration, request func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	token := client.tokenProvider.Token()
+	err := request(ctx)
+	renewed, err := client.reloadAccessTokenOnAuthError(err, token)
+	if renewed {
+		// Try again with new Access Token
+		err = request(ctx)
+	}
+
+	return err
+}
+
+// isRetryable checks if the error of a Consul request is caused by a transient failure
+func isRetryable(err error) bool {
+	var statusErr consulapi.StatusError
+	if errors.As(err, &statusErr) {
+		return retry.IsRetryableStatus(statusErr.Code)
+	}
+
+	return retry.IsNetworkError(err)
+}
+
+// reloadAccessTokenOnAuthError renews the Access Token if the error of a request made with token shows it has been
+// rejected, and reports whether the request can be made again. The renewal is shared by all requests rejected
+// simultaneously.
+func (client *consulClient) reloadAccessTokenOnAuthError(err error, token string) (bool, error) {
+	if err == nil {
+		return false, nil
+	}
+
+	isAuthError := strings.Contains(err.Error(), aclError) || strings.Contains(err.Error(), aclDeniedError)
+	if isAuthError && client.tokenProvider.CanRenew() {
+		// The renewed Access Token is used by the next request, no need to recreate the consul client
+		if err := client.tokenProvider.RenewRejected(token); err != nil {
 			return false, err
 		}
 
@@ -414,11 +875,54 @@ func (client *consulClient) fullPath(name string) string {
 	return client.configBasePath + name
 }
 
+func queryOptions(ctx context.Context) *consulapi.QueryOptions {
+	return (&consulapi.QueryOptions{}).WithContext(ctx)
+}
+
+func writeOptions(ctx context.Context) *consulapi.WriteOptions {
+	return (&consulapi.WriteOptions{}).WithContext(ctx)
+}
+
 type pair struct {
 	Key   string
 	Value string
 }
 
+// sleep waits for the duration, returning false if the context is done before
+func sleep(ctx context.Context, duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// txnError converts the errors of a rolled back transaction to a single error
+func txnError(response *consulapi.TxnResponse) error {
+	if response == nil || len(response.Errors) == 0 {
+		return errors.New("transaction was rolled back")
+	}
+
+	messages := make([]string, 0, len(response.Errors))
+	for _, txnErr := range response.Errors {
+		messages = append(messages, fmt.Sprintf("operation %d: %s", txnErr.OpIndex, txnErr.What))
+	}
+
+	return fmt.Errorf("transaction was rolled back: %s", strings.Join(messages, ", "))
+}
+
+func pairKeys(pairs []*pair) []string {
+	keys := make([]string, 0, len(pairs))
+	for _, keyValue := range pairs {
+		keys = append(keys, keyValue.Key)
+	}
+	return keys
+}
+
 func convertInterfaceToConsulPairs(path string, interfaceMap interface{}) []*pair {
 	pairs := make([]*pair, 0)
 
@@ -465,8 +969,13 @@ func convertInterfaceToConsulPairs(path string, interfaceMap interface{}) []*pai
 
 func (client *consulClient) newConsulDecoder() *consulstructure.Decoder {
 	return &consulstructure.Decoder{
-		Consul: &consulapi.Config{
-			Address: client.consulUrl,
-		},
+		Consul: client.newConsulConfig(),
 	}
 }
+
+// newConsulConfig returns a copy of the consul config for a decoder, since the decoder modifies the config it is
+// given when creating its consul client, which would race with the other decoders
+func (client *consulClient) newConsulConfig() *consulapi.Config {
+	config := *client.consulConfig
+	return &config
+}
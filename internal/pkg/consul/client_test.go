@@ -18,13 +18,18 @@
 package consul
 
 import (
+	"context"
+	"encoding/pem"
 	"fmt"
+	"net"
+	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -331,6 +336,158 @@ func TestPutConfigurationValue(t *testing.T) {
 
 }
 
+func TestDeleteConfigurationValue(t *testing.T) {
+	client := makeConsulClient(t, getUniqueServiceName(), "", nil)
+
+	// Make sure the configuration doesn't already exist
+	reset(t, client)
+
+	require.NoError(t, client.PutConfigurationValue("Foo", []byte("bar")))
+	require.NoError(t, client.PutConfigurationValue("FooBar", []byte("bar")))
+
+	err := client.DeleteConfigurationValue("Foo")
+	require.NoError(t, err)
+
+	exists, err := client.ConfigurationValueExists("Foo")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	exists, err = client.ConfigurationValueExists("FooBar")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	// Deleting a value which doesn't exist is not an error
+	err = client.DeleteConfigurationValue("Foo")
+	require.NoError(t, err)
+}
+
+func TestDeleteSubConfiguration(t *testing.T) {
+	client := makeConsulClient(t, getUniqueServiceName(), "", nil)
+
+	// Make sure the configuration doesn't already exist
+	reset(t, client)
+
+	require.NoError(t, client.PutConfigurationValue("Writable/LogLevel", []byte("INFO")))
+	require.NoError(t, client.PutConfigurationValue("Writable/InsecureSecrets/DB/Path", []byte("redisdb")))
+	require.NoError(t, client.PutConfigurationValue("WritableExtra", []byte("true")))
+
+	err := client.DeleteSubConfiguration("Writable")
+	require.NoError(t, err)
+
+	keys, err := client.GetConfigurationKeys("Writable")
+	require.NoError(t, err)
+	assert.Equal(t, []string{client.fullPath("WritableExtra")}, keys)
+
+	// Deleting a sub configuration which doesn't exist is not an error
+	err = client.DeleteSubConfiguration("Writable")
+	require.NoError(t, err)
+}
+
+func TestPutConfigurationValueCAS(t *testing.T) {
+	client := makeConsulClient(t, getUniqueServiceName(), "", nil)
+
+	// Make sure the configuration doesn't already exist
+	reset(t, client)
+
+	value, version, err := client.GetConfigurationValueWithVersion("Writable/LogLevel")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+	assert.Zero(t, version)
+
+	// Version 0 only creates the value if it doesn't exist
+	require.NoError(t, client.PutConfigurationValueCAS("Writable/LogLevel", []byte("INFO"), 0))
+
+	value, version, err = client.GetConfigurationValueWithVersion("Writable/LogLevel")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("INFO"), value)
+	assert.NotZero(t, version)
+
+	// Another operator changes the value in the meantime
+	require.NoError(t, client.PutConfigurationValue("Writable/LogLevel", []byte("DEBUG")))
+
+	err = client.PutConfigurationValueCAS("Writable/LogLevel", []byte("WARN"), version)
+	var conflictErr *types.ConflictError
+	require.ErrorAs(t, err, &conflictErr)
+	assert.Equal(t, version, conflictErr.ExpectedVersion)
+	assert.NotEqual(t, version, conflictErr.ActualVersion)
+
+	_, version, err = client.GetConfigurationValueWithVersion("Writable/LogLevel")
+	require.NoError(t, err)
+	require.NoError(t, client.PutConfigurationValueCAS("Writable/LogLevel", []byte("WARN"), version))
+
+	value, err = client.GetConfigurationValue("Writable/LogLevel")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("WARN"), value)
+
+	err = client.PutConfigurationValueCAS("Writable/LogLevel", []byte("ERROR"), 0)
+	require.ErrorAs(t, err, &conflictErr)
+}
+
+// makeUnavailableConsulClient makes a client of a Consul server holding no keys and dropping the connection of all
+// the writes, which are counted by writes
+func makeUnavailableConsulClient(t *testing.T, writes *atomic.Int32) *consulClient {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method == http.MethodGet {
+			writer.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writes.Add(1)
+		conn, _, err := writer.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		_ = conn.Close()
+	}))
+	t.Cleanup(server.Close)
+
+	serverUrl, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	serverPort, err := strconv.Atoi(serverUrl.Port())
+	require.NoError(t, err)
+
+	client, err := NewConsulClient(types.ServiceConfig{
+		Host:     serverUrl.Hostname(),
+		Port:     serverPort,
+		BasePath: consulBasePath + getUniqueServiceName(),
+		Retry:    types.RetryInfo{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+	})
+	require.NoError(t, err)
+
+	return client
+}
+
+func TestPutConfigurationValueCASNotRetried(t *testing.T) {
+	var writes atomic.Int32
+	client := makeUnavailableConsulClient(t, &writes)
+
+	// unconditional writes are retried
+	require.Error(t, client.PutConfigurationValue("Writable/LogLevel", []byte("INFO")))
+	assert.Equal(t, int32(3), writes.Swap(0))
+
+	// the conditional write may have been applied despite the failure, so it is made once
+	err := client.PutConfigurationValueCAS("Writable/LogLevel", []byte("INFO"), 1)
+	require.Error(t, err)
+	var netErr net.Error
+	require.ErrorAs(t, err, &netErr)
+	assert.Equal(t, int32(1), writes.Load())
+}
+
+func TestPutConfigurationMapBatchCASNotRetried(t *testing.T) {
+	var writes atomic.Int32
+	client := makeUnavailableConsulClient(t, &writes)
+	configuration := map[string]any{"Writable": map[string]any{"LogLevel": "INFO"}}
+
+	// transactions setting the keys are retried
+	result, err := client.PutConfigurationMapBatch(context.Background(), configuration, true)
+	require.Error(t, err)
+	assert.Equal(t, []string{"Writable/LogLevel"}, result.Failed)
+	assert.Equal(t, int32(3), writes.Swap(0))
+
+	// without overwrite, the check-and-set transaction may have been applied despite the failure, so it is made once
+	result, err = client.PutConfigurationMapBatch(context.Background(), configuration, false)
+	require.Error(t, err)
+	assert.Equal(t, []string{"Writable/LogLevel"}, result.Failed)
+	assert.Equal(t, int32(1), writes.Load())
+}
+
 func TestGetConfiguration(t *testing.T) {
 	expected := MyConfig{
 		Logging: LoggingInfo{
@@ -538,6 +695,47 @@ func TestPutConfigurationMapOverWrite(t *testing.T) {
 	}
 }
 
+func TestPutConfigurationMapBatch(t *testing.T) {
+	client := makeConsulClient(t, getUniqueServiceName(), "", nil)
+
+	// Make sure the configuration doesn't already exist
+	reset(t, client)
+
+	// More keys than fit in a single transaction
+	configMap := make(map[string]any)
+	var expectedKeys []string
+	for i := 0; i < maxTxnOps+10; i++ {
+		key := fmt.Sprintf("key%03d", i)
+		configMap[key] = i
+		expectedKeys = append(expectedKeys, key)
+	}
+
+	require.NoError(t, client.PutConfigurationValue("key000", []byte("existing")))
+
+	result, err := client.PutConfigurationMapBatch(context.Background(), configMap, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"key000"}, result.Skipped)
+	assert.Equal(t, expectedKeys[1:], result.Written)
+	assert.Empty(t, result.Failed)
+
+	value, err := client.GetConfigurationValue("key000")
+	require.NoError(t, err)
+	assert.Equal(t, "existing", string(value))
+
+	value, err = client.GetConfigurationValue("key073")
+	require.NoError(t, err)
+	assert.Equal(t, "73", string(value))
+
+	result, err = client.PutConfigurationMapBatch(context.Background(), configMap, true)
+	require.NoError(t, err)
+	assert.Equal(t, expectedKeys, result.Written)
+	assert.Empty(t, result.Skipped)
+
+	value, err = client.GetConfigurationValue("key000")
+	require.NoError(t, err)
+	assert.Equal(t, "0", string(value))
+}
+
 func TestWatchForChanges(t *testing.T) {
 	expectedConfig := MyConfig{
 		Logging: LoggingInfo{
@@ -605,6 +803,178 @@ func TestWatchForChanges(t *testing.T) {
 	}
 }
 
+func TestWatchForChangesDebounce(t *testing.T) {
+	client := makeConsulClient(t, getUniqueServiceName(), "", nil)
+	// the mock Consul only returns the last changes once its blocking query of 1s has timed out,
+	// so the debounce window must be longer for the burst to be seen as a single change
+	client.debounceWindow = 1500 * time.Millisecond
+
+	// Make sure the tree of values doesn't exist.
+	_, _ = client.consulClient.KV().DeleteTree(consulBasePath, nil)
+	// Clean up after unit test
+	defer func() {
+		_, _ = client.consulClient.KV().DeleteTree(consulBasePath, nil)
+	}()
+
+	require.NoError(t, client.PutConfigurationValue("Logging/File", []byte("NONE")))
+
+	updateChannel := make(chan interface{})
+	errorChannel := make(chan error)
+
+	client.WatchForChanges(updateChannel, errorChannel, &LoggingInfo{}, "Logging", nil)
+	defer client.StopWatching()
+
+	receive := func() *LoggingInfo {
+		select {
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting on Logging configuration update")
+		case update := <-updateChannel:
+			require.IsType(t, &LoggingInfo{}, update)
+			return update.(*LoggingInfo)
+		case err := <-errorChannel:
+			t.Fatalf("received WatchForChanges error for Logging: %v", err)
+		}
+		return nil
+	}
+
+	// Consul Decoder always sends data once the watch has been set up
+	assert.Equal(t, "NONE", receive().File)
+
+	// a burst of changes results in a single update
+	for _, file := range []string{"first", "second", "last"} {
+		require.NoError(t, client.PutConfigurationValue("Logging/File", []byte(file)))
+	}
+	assert.Equal(t, "last", receive().File)
+
+	select {
+	case update := <-updateChannel:
+		t.Fatalf("unexpected update: %v", update)
+	case <-time.After(client.debounceWindow):
+	}
+}
+
+func TestWatchForEvents(t *testing.T) {
+	client := makeConsulClient(t, getUniqueServiceName(), "", nil)
+
+	// Make sure the tree of values doesn't exist.
+	_, _ = client.consulClient.KV().DeleteTree(consulBasePath, nil)
+	// Clean up after unit test
+	defer func() {
+		_, _ = client.consulClient.KV().DeleteTree(consulBasePath, nil)
+	}()
+
+	require.NoError(t, client.PutConfigurationValue("Logging/EnableRemote", []byte("true")))
+	require.NoError(t, client.PutConfigurationValue("Logging/File", []byte("NONE")))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	eventChannel := make(chan types.ChangeEvent)
+	errorChannel := make(chan error)
+
+	client.WatchForEvents(ctx, eventChannel, errorChannel, "Logging", nil)
+
+	// The mock only answers the first query, which is the baseline, once it times out
+	time.Sleep(1500 * time.Millisecond)
+
+	expectedEvents := []struct {
+		change   func()
+		expected types.ChangeEvent
+	}{
+		{
+			change: func() { _ = client.PutConfigurationValue("Logging/File", []byte("random")) },
+			expected: types.ChangeEvent{
+				Key: "Logging/File", OldValue: []byte("NONE"), NewValue: []byte("random"), Type: types.ChangeModified,
+			},
+		},
+		{
+			change: func() { _ = client.DeleteConfigurationValue("Logging/EnableRemote") },
+			expected: types.ChangeEvent{
+				Key: "Logging/EnableRemote", OldValue: []byte("true"), Type: types.ChangeDeleted,
+			},
+		},
+		{
+			change: func() { _ = client.PutConfigurationValue("Logging/Level", []byte("INFO")) },
+			expected: types.ChangeEvent{
+				Key: "Logging/Level", NewValue: []byte("INFO"), Type: types.ChangeAdded,
+			},
+		},
+	}
+
+	for _, test := range expectedEvents {
+		test.change()
+
+		select {
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting on %s event for %s", test.expected.Type, test.expected.Key)
+
+		case event := <-eventChannel:
+			assert.NotZero(t, event.Index)
+			event.Index = 0
+			assert.Equal(t, test.expected, event)
+
+		case waitError := <-errorChannel:
+			t.Fatalf("received WatchForEvents error for Logging: %v", waitError)
+		}
+	}
+
+	cancel()
+	client.StopWatching()
+}
+
+func TestWatch(t *testing.T) {
+	client := makeConsulClient(t, getUniqueServiceName(), "", nil)
+
+	// Make sure the tree of values doesn't exist.
+	_, _ = client.consulClient.KV().DeleteTree(consulBasePath, nil)
+	// Clean up after unit test
+	defer func() {
+		_, _ = client.consulClient.KV().DeleteTree(consulBasePath, nil)
+	}()
+
+	require.NoError(t, client.PutConfigurationValue("Logging/File", []byte("NONE")))
+	require.NoError(t, client.PutConfigurationValue("Writable/LogLevel", []byte("INFO")))
+
+	type WritableInfo struct {
+		LogLevel string
+	}
+
+	loggingUpdates := make(chan interface{})
+	writableUpdates := make(chan interface{})
+	errorChannel := make(chan error)
+
+	loggingWatcher := client.Watch(context.Background(), loggingUpdates, errorChannel, &LoggingInfo{}, "Logging", nil)
+	writableWatcher := client.Watch(context.Background(), writableUpdates, errorChannel, &WritableInfo{}, "Writable", nil)
+	defer writableWatcher.Stop()
+
+	// Consul Decoder always sends data once the watch has been set up
+	for _, updates := range []chan interface{}{loggingUpdates, writableUpdates} {
+		select {
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting on initial configuration")
+		case update := <-updates:
+			assert.NotNil(t, update)
+		case err := <-errorChannel:
+			t.Fatalf("received Watch error: %v", err)
+		}
+	}
+
+	loggingWatcher.Stop()
+	assert.ErrorIs(t, loggingWatcher.Err(), context.Canceled)
+	assert.NoError(t, writableWatcher.Err())
+
+	require.NoError(t, client.PutConfigurationValue("Writable/LogLevel", []byte("DEBUG")))
+
+	select {
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting on Writable update")
+	case update := <-writableUpdates:
+		assert.Equal(t, "DEBUG", update.(*WritableInfo).LogLevel)
+	case err := <-errorChannel:
+		t.Fatalf("received Watch error: %v", err)
+	}
+}
+
 func TestAccessToken(t *testing.T) {
 	uniqueServiceName := getUniqueServiceName()
 	client := makeConsulClient(t, uniqueServiceName, "", nil)
@@ -820,8 +1190,8 @@ func TestRenewAccessToken(t *testing.T) {
 						return
 					}
 					require.NotNil(t, raw)
-					wg.Done()
 					receivedUpdate = true
+					wg.Done()
 					fmt.Println("WatchForChanges update received")
 					return
 				}
@@ -841,7 +1211,7 @@ func TestRenewAccessToken(t *testing.T) {
 		putTestConfig()
 		client := createClient(false)
 
-		allStopped := false
+		allStopped := make(chan struct{})
 		updates := make(chan interface{})
 		errs := make(chan error)
 		client.WatchForChanges(updates, errs, &myConfig, "Host", nil)
@@ -849,10 +1219,244 @@ func TestRenewAccessToken(t *testing.T) {
 
 		go func() {
 			client.StopWatching()
-			allStopped = true
+			close(allStopped)
 		}()
 
-		<-time.Tick(2 * time.Second)
-		assert.True(t, allStopped)
+		select {
+		case <-allStopped:
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout waiting for the watches to stop")
+		}
+	})
+}
+
+func TestAccessTokenRenewedAheadOfExpiry(t *testing.T) {
+	// the first token expires quickly, the renewed one doesn't
+	var renewals atomic.Int32
+	client, err := NewConsulClient(types.ServiceConfig{
+		Host:     testHost,
+		Port:     port,
+		BasePath: consulBasePath + getUniqueServiceName(),
+		GetAccessTokenWithTTL: func() (string, time.Duration, error) {
+			if renewals.Add(1) == 1 {
+				return "ShortLivedToken", 100 * time.Millisecond, nil
+			}
+			return "GoodToken", time.Hour, nil
+		},
 	})
+	require.NoError(t, err)
+	defer client.tokenProvider.Stop()
+
+	require.Eventually(t, func() bool {
+		return renewals.Load() == 2
+	}, time.Second, 10*time.Millisecond)
+
+	// no request has to be rejected for the renewed token to be used
+	if mockConsul != nil {
+		mockConsul.SetExpectedAccessToken("GoodToken")
+		defer mockConsul.ClearExpectedAccessToken()
+	}
+	require.NoError(t, client.PutConfigurationValue("Port", []byte("8000")))
+	assert.Equal(t, int32(2), renewals.Load())
+}
+
+func TestContextCanceled(t *testing.T) {
+	client := makeConsulClient(t, getUniqueServiceName(), "", nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.GetConfigurationValueCtx(ctx, "Dummy")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), context.Canceled.Error())
+
+	err = client.PutConfigurationValueCtx(ctx, "Dummy", []byte("Value"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), context.Canceled.Error())
+
+	_, err = client.GetConfigurationCtx(ctx, &MyConfig{})
+	require.Error(t, err)
+
+	assert.False(t, client.IsAliveCtx(ctx))
+}
+
+func TestTimeouts(t *testing.T) {
+	slowServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		time.Sleep(500 * time.Millisecond)
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer slowServer.Close()
+
+	URL, _ := url.Parse(slowServer.URL)
+	slowPort, _ := strconv.Atoi(URL.Port())
+	client, err := NewConsulClient(types.ServiceConfig{
+		Host:     URL.Hostname(),
+		Port:     slowPort,
+		BasePath: consulBasePath + getUniqueServiceName(),
+		Timeouts: types.TimeoutInfo{
+			Load:     50 * time.Millisecond,
+			Read:     50 * time.Millisecond,
+			Write:    50 * time.Millisecond,
+			Liveness: 50 * time.Millisecond,
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = client.GetConfigurationValue("Dummy")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), context.DeadlineExceeded.Error())
+
+	err = client.PutConfigurationValue("Dummy", []byte("Value"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), context.DeadlineExceeded.Error())
+
+	_, err = client.GetConfiguration(&MyConfig{})
+	require.Error(t, err)
+
+	assert.False(t, client.IsAlive())
+}
+
+func TestTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "application/json")
+		switch request.URL.Path {
+		case consulStatusPath:
+			_, _ = writer.Write([]byte(`"127.0.0.1:8300"`))
+		case "/v1/kv/edgex/tls/Port":
+			_, _ = writer.Write([]byte(`[{"Key": "edgex/tls/Port", "Value": "ODAwMA=="}]`))
+		default:
+			writer.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	serverUrl, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	serverPort, err := strconv.Atoi(serverUrl.Port())
+	require.NoError(t, err)
+
+	makeClient := func(tls types.TLSInfo) *consulClient {
+		client, err := NewConsulClient(types.ServiceConfig{
+			Protocol: "https",
+			Host:     serverUrl.Hostname(),
+			Port:     serverPort,
+			BasePath: "edgex/tls",
+			TLS:      tls,
+		})
+		require.NoError(t, err)
+		return client
+	}
+
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	client := makeClient(types.TLSInfo{CAPEM: caPEM, ServerName: "example.com"})
+
+	assert.True(t, client.IsAlive())
+	value, err := client.GetConfigurationValue("Port")
+	require.NoError(t, err)
+	assert.Equal(t, "8000", string(value))
+
+	t.Run("Untrusted", func(t *testing.T) {
+		client := makeClient(types.TLSInfo{})
+
+		assert.False(t, client.IsAlive())
+		_, err := client.GetConfigurationValue("Port")
+		require.Error(t, err)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := NewConsulClient(types.ServiceConfig{Host: "localhost", Port: 8500, TLS: types.TLSInfo{MinVersion: "0.9"}})
+		require.Error(t, err)
+	})
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	const goodToken = "GoodToken"
+	concurrentMock := NewMockConsul()
+	concurrentMock.SetExpectedAccessToken(goodToken)
+	server := concurrentMock.Start()
+	defer server.Close()
+
+	serverUrl, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	serverPort, err := strconv.Atoi(serverUrl.Port())
+	require.NoError(t, err)
+
+	var renewals atomic.Int32
+	client, err := NewConsulClient(types.ServiceConfig{
+		Host:        serverUrl.Hostname(),
+		Port:        serverPort,
+		BasePath:    consulBasePath + getUniqueServiceName(),
+		AccessToken: "BadToken",
+		GetAccessToken: func() (string, error) {
+			renewals.Add(1)
+			// leave time for the other rejected requests to join the renewal
+			time.Sleep(50 * time.Millisecond)
+			return goodToken, nil
+		},
+	})
+	require.NoError(t, err)
+
+	const goroutines = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines)
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := "Key" + strconv.Itoa(i)
+			if err := client.PutConfigurationValue(key, []byte(strconv.Itoa(i))); err != nil {
+				errs <- err
+				return
+			}
+			value, err := client.GetConfigurationValue(key)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if string(value) != strconv.Itoa(i) {
+				errs <- fmt.Errorf("unexpected value '%s' for %s", value, key)
+				return
+			}
+			if _, err := client.HasConfiguration(); err != nil {
+				errs <- err
+				return
+			}
+			if _, err := client.GetConfiguration(&MyConfig{}); err != nil {
+				errs <- err
+				return
+			}
+			client.StopWatching()
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), renewals.Load(), "simultaneous rejections must renew the access token once")
+}
+
+func TestCloseStopsAccessTokenRenewal(t *testing.T) {
+	var renewals atomic.Int32
+	client, err := NewConsulClient(types.ServiceConfig{
+		Host:     testHost,
+		Port:     port,
+		BasePath: consulBasePath + getUniqueServiceName(),
+		GetAccessTokenWithTTL: func() (string, time.Duration, error) {
+			renewals.Add(1)
+			return "ShortLivedToken", 40 * time.Millisecond, nil
+		},
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return renewals.Load() >= 3
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, client.Close())
+	closedRenewals := renewals.Load()
+
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, closedRenewals, renewals.Load(), "the access token must not be renewed once the client is closed")
 }
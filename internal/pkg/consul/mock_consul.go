@@ -24,6 +24,7 @@ import (
 	"net/http/httptest"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	consulapi "github.com/hashicorp/consul/api"
@@ -34,11 +35,27 @@ const (
 	TokenKey = "X-Consul-Token" // nolint: gosec
 )
 
+// MockConsul is a minimal Consul server for unit tests, safe for concurrent use
 type MockConsul struct {
+	// lock guards all the fields below
+	lock                sync.Mutex
 	keyValueStore       map[string]*consulapi.KVPair
 	serviceStore        map[string]consulapi.AgentService
 	serviceCheckStore   map[string]consulapi.AgentCheck
 	expectedAccessToken string
+	// consulIndex is the index of the blocking queries, which is incremented whenever a watched prefix changes
+	consulIndex int
+	// watchedPrefixes are the prefixes which have been watched, their changes increment the index even when no
+	// blocking query is waiting for them
+	watchedPrefixes map[string]struct{}
+	// prefixWatches are the blocking queries waiting for a change of the keys under their prefix
+	prefixWatches map[*prefixWatch]struct{}
+}
+
+// prefixWatch is a blocking query waiting for a change of the keys under prefix
+type prefixWatch struct {
+	prefix  string
+	changed chan struct{}
 }
 
 func NewMockConsul() *MockConsul {
@@ -46,28 +63,118 @@ func NewMockConsul() *MockConsul {
 		keyValueStore:     make(map[string]*consulapi.KVPair),
 		serviceStore:      make(map[string]consulapi.AgentService),
 		serviceCheckStore: make(map[string]consulapi.AgentCheck),
+		consulIndex:       1,
+		watchedPrefixes:   make(map[string]struct{}),
+		prefixWatches:     make(map[*prefixWatch]struct{}),
 	}
 }
 
-var keyChannels map[string]chan bool
-var PrefixChannels map[string]chan bool
-
 func (mock *MockConsul) Reset() {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+
 	mock.keyValueStore = make(map[string]*consulapi.KVPair)
 	mock.serviceStore = make(map[string]consulapi.AgentService)
 	mock.serviceCheckStore = make(map[string]consulapi.AgentCheck)
 }
 
-func (mock *MockConsul) Start() *httptest.Server {
-	keyChannels = make(map[string]chan bool)
-	var consulIndex = 1
-	PrefixChannels = make(map[string]chan bool)
+// putKey stores the value of the key, the lock must be held
+func (mock *MockConsul) putKey(key string, value []byte) {
+	keyValuePair, found := mock.keyValueStore[key]
+	if found {
+		keyValuePair.ModifyIndex++
+		keyValuePair.Value = value
+	} else {
+		keyValuePair = &consulapi.KVPair{
+			Key:         key,
+			Value:       value,
+			ModifyIndex: 1,
+			CreateIndex: 1,
+			Flags:       0,
+			LockIndex:   0,
+		}
+	}
+
+	mock.keyValueStore[key] = keyValuePair
+
+	if verbose {
+		log.Printf("PUTing new value for %s", key)
+	}
+}
+
+// notifyKeys increments the index for each watched prefix of the keys and wakes up the blocking queries watching
+// them, each of them only once. The lock must be held.
+func (mock *MockConsul) notifyKeys(keys ...string) {
+	for prefix := range mock.watchedPrefixes {
+		if hasPrefix(keys, prefix) {
+			mock.consulIndex++
+		}
+	}
+	for watch := range mock.prefixWatches {
+		if hasPrefix(keys, watch.prefix) {
+			select {
+			case watch.changed <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// hasPrefix returns whether any of the keys starts with prefix
+func hasPrefix(keys []string, prefix string) bool {
+	for _, key := range keys {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// waitForNextPutPrefix blocks until a key under the prefix changes, the wait time is over or the request is
+// cancelled. The lock must not be held.
+func (mock *MockConsul) waitForNextPutPrefix(request *http.Request, prefix string, waitTime time.Duration) {
+	watch := &prefixWatch{prefix: prefix, changed: make(chan struct{}, 1)}
 
+	mock.lock.Lock()
+	mock.watchedPrefixes[prefix] = struct{}{}
+	mock.prefixWatches[watch] = struct{}{}
+	mock.lock.Unlock()
+
+	if verbose {
+		log.Printf("Watching for change on %s", prefix)
+	}
+
+	timer := time.NewTimer(waitTime)
+	defer timer.Stop()
+
+	select {
+	case <-watch.changed:
+		if verbose {
+			log.Printf("%s changed", prefix)
+		}
+	case <-timer.C:
+		if verbose {
+			log.Printf("Timed out watching for change on %s", prefix)
+		}
+	case <-request.Context().Done():
+	}
+
+	mock.lock.Lock()
+	delete(mock.prefixWatches, watch)
+	mock.lock.Unlock()
+}
+
+func (mock *MockConsul) Start() *httptest.Server {
 	testMockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
-		if len(mock.expectedAccessToken) > 0 {
+		mock.lock.Lock()
+		expectedAccessToken := mock.expectedAccessToken
+		mock.lock.Unlock()
+
+		if len(expectedAccessToken) > 0 {
 			token := request.Header.Get(TokenKey)
-			if len(mock.expectedAccessToken) > 0 && token != mock.expectedAccessToken {
+			if token != expectedAccessToken {
 				writer.WriteHeader(http.StatusForbidden)
+				_, _ = writer.Write([]byte(aclDeniedError))
 				return
 			}
 		}
@@ -82,39 +189,25 @@ func (mock *MockConsul) Start() *httptest.Server {
 					log.Printf("error reading request body: %s", err.Error())
 				}
 
-				keyValuePair, found := mock.keyValueStore[key]
-				if found {
-					keyValuePair.ModifyIndex++
-					keyValuePair.Value = body
-				} else {
-					keyValuePair = &consulapi.KVPair{
-						Key:         key,
-						Value:       body,
-						ModifyIndex: 1,
-						CreateIndex: 1,
-						Flags:       0,
-						LockIndex:   0,
+				mock.lock.Lock()
+				defer mock.lock.Unlock()
+
+				// Check-and-set parameter is set when the key is only to be written if its ModifyIndex matches
+				if cas := request.URL.Query().Get("cas"); cas != "" {
+					index, _ := strconv.ParseUint(cas, 10, 64)
+					existing, found := mock.keyValueStore[key]
+					if (index == 0 && found) || (index != 0 && (!found || existing.ModifyIndex != index)) {
+						writer.WriteHeader(http.StatusOK)
+						_, _ = writer.Write([]byte("false"))
+						return
 					}
 				}
 
-				mock.keyValueStore[key] = keyValuePair
-
-				if verbose {
-					log.Printf("PUTing new value for %s", key)
-				}
+				mock.putKey(key, body)
+				mock.notifyKeys(key)
 
-				channel, found := keyChannels[key]
-				if found {
-					channel <- true
-				}
-				for prefix, channel := range PrefixChannels {
-					if strings.HasPrefix(key, prefix) {
-						consulIndex++
-						if channel != nil {
-							channel <- true
-						}
-					}
-				}
+				writer.WriteHeader(http.StatusOK)
+				_, _ = writer.Write([]byte("true"))
 			case "GET":
 				// this is what the wait query parameters will look like "index=1&wait=600000ms"
 				var pairs consulapi.KVPairs
@@ -132,11 +225,14 @@ func (mock *MockConsul) Start() *httptest.Server {
 						return
 					}
 					//Default wait time is 30 minutes, over riding it for unit test purpose
-					waitTime := "1s"
-					if waitTime != "" {
-						waitForNextPutPrefix(key, waitTime)
-					}
-					writer.Header().Set("X-Consul-Index", strconv.Itoa(consulIndex))
+					mock.waitForNextPutPrefix(request, key, time.Second)
+
+					// the keys are read again to return their changes made while waiting
+					pairs, _ = mock.checkForPrefix(key)
+
+					mock.lock.Lock()
+					writer.Header().Set("X-Consul-Index", strconv.Itoa(mock.consulIndex))
+					mock.lock.Unlock()
 				} else if allKeysRequested {
 					// Just returning array of key names
 					var keys []string
@@ -158,10 +254,14 @@ func (mock *MockConsul) Start() *httptest.Server {
 					if _, err := writer.Write(jsonData); err != nil {
 						log.Printf("error writing data response: %s", err.Error())
 					}
-
+					return
 				} else {
+					mock.lock.Lock()
 					keyValuePair, found := mock.keyValueStore[key]
-					pairs = consulapi.KVPairs{keyValuePair}
+					if found {
+						pairs = consulapi.KVPairs{copyPair(keyValuePair)}
+					}
+					mock.lock.Unlock()
 					if !found {
 						http.NotFound(writer, request)
 						return
@@ -175,6 +275,78 @@ func (mock *MockConsul) Start() *httptest.Server {
 				if _, err := writer.Write(jsonData); err != nil {
 					log.Printf("error writing data response: %s", err.Error())
 				}
+			case "DELETE":
+				mock.lock.Lock()
+				defer mock.lock.Unlock()
+
+				// Recurse parameter is set when the whole tree under the key is deleted
+				var deletedKeys []string
+				if _, recurseFound := request.URL.Query()["recurse"]; recurseFound {
+					for existing := range mock.keyValueStore {
+						if strings.HasPrefix(existing, key) {
+							delete(mock.keyValueStore, existing)
+							deletedKeys = append(deletedKeys, existing)
+						}
+					}
+				} else if _, found := mock.keyValueStore[key]; found {
+					delete(mock.keyValueStore, key)
+					deletedKeys = append(deletedKeys, key)
+				}
+				mock.notifyKeys(deletedKeys...)
+
+				if verbose {
+					log.Printf("DELETEing %s", key)
+				}
+
+				writer.Header().Set("Content-Type", "application/json")
+				writer.WriteHeader(http.StatusOK)
+				if _, err := writer.Write([]byte("true")); err != nil {
+					log.Printf("error writing data response: %s", err.Error())
+				}
+			}
+		} else if strings.Contains(request.URL.Path, "/v1/txn") {
+			switch request.Method {
+			case "PUT":
+				var ops consulapi.TxnOps
+				if err := json.NewDecoder(request.Body).Decode(&ops); err != nil {
+					log.Printf("error decoding request body: %s", err.Error())
+				}
+
+				mock.lock.Lock()
+				defer mock.lock.Unlock()
+
+				// Check all operations first so that none is applied if any of them fails
+				var response consulapi.TxnResponse
+				for index, op := range ops {
+					if op.KV == nil || (op.KV.Verb != consulapi.KVSet && op.KV.Verb != consulapi.KVCAS) {
+						response.Errors = append(response.Errors, &consulapi.TxnError{OpIndex: index, What: "unsupported operation"})
+						continue
+					}
+					if op.KV.Verb == consulapi.KVCAS {
+						existing, found := mock.keyValueStore[op.KV.Key]
+						if (op.KV.Index == 0 && found) || (op.KV.Index != 0 && (!found || existing.ModifyIndex != op.KV.Index)) {
+							response.Errors = append(response.Errors, &consulapi.TxnError{OpIndex: index, What: "failed to set key " + op.KV.Key + ", index is stale"})
+						}
+					}
+				}
+
+				status := http.StatusConflict
+				if len(response.Errors) == 0 {
+					status = http.StatusOK
+					keys := make([]string, 0, len(ops))
+					for _, op := range ops {
+						mock.putKey(op.KV.Key, op.KV.Value)
+						keys = append(keys, op.KV.Key)
+						response.Results = append(response.Results, &consulapi.TxnResult{KV: copyPair(mock.keyValueStore[op.KV.Key])})
+					}
+					mock.notifyKeys(keys...)
+				}
+
+				writer.Header().Set("Content-Type", "application/json")
+				writer.WriteHeader(status)
+				if err := json.NewEncoder(writer).Encode(&response); err != nil {
+					log.Printf("error writing data response: %s", err.Error())
+				}
 			}
 		} else if strings.Contains(request.URL.Path, "/v1/status/leader") {
 			switch request.Method {
@@ -189,42 +361,15 @@ func (mock *MockConsul) Start() *httptest.Server {
 	return testMockServer
 }
 
-func waitForNextPutPrefix(key string, waitTime string) {
-	timeout, err := time.ParseDuration(waitTime)
-	if err != nil {
-		log.Printf("Error parsing waitTime %s into a duration: %s", waitTime, err.Error())
-	}
-	channel := make(chan bool)
-	PrefixChannels[key] = channel
-	timedOut := false
-	go func() {
-		time.Sleep(timeout)
-		timedOut = true
-		if PrefixChannels[key] != nil {
-			PrefixChannels[key] <- true
-			if verbose {
-				log.Printf("Timed out watching for change on %s", key)
-			}
-		}
-	}()
-
-	if verbose {
-		log.Printf("Watching for change on %s", key)
-	}
-
-	<-channel
-	close(channel)
-	PrefixChannels[key] = nil
-	if !timedOut {
-		log.Printf("%s changed", key)
-	}
-}
-
+// checkForPrefix returns a copy of the pairs of the keys under prefix
 func (mock *MockConsul) checkForPrefix(prefix string) (consulapi.KVPairs, bool) {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+
 	var pairs consulapi.KVPairs
 	for k, v := range mock.keyValueStore {
 		if strings.HasPrefix(k, prefix) {
-			pairs = append(pairs, v)
+			pairs = append(pairs, copyPair(v))
 		}
 	}
 	if len(pairs) == 0 {
@@ -234,10 +379,22 @@ func (mock *MockConsul) checkForPrefix(prefix string) (consulapi.KVPairs, bool)
 
 }
 
+// copyPair copies the pair, so that it can be encoded while its stored value is updated
+func copyPair(pair *consulapi.KVPair) *consulapi.KVPair {
+	copied := *pair
+	return &copied
+}
+
 func (mock *MockConsul) SetExpectedAccessToken(token string) {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+
 	mock.expectedAccessToken = token
 }
 
 func (mock *MockConsul) ClearExpectedAccessToken() {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+
 	mock.expectedAccessToken = ""
 }
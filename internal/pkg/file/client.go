@@ -0,0 +1,410 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/kvtree"
+	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/watch"
+	"github.com/edgexfoundry/go-mod-configuration/v3/pkg/types"
+
+	"github.com/edgexfoundry/go-mod-messaging/v3/messaging"
+)
+
+const defaultPollInterval = time.Second
+
+type fileClient struct {
+	location       string
+	store          store
+	configBasePath string
+	pollInterval   time.Duration
+	storeMutex     sync.Mutex
+	// watchMutex guards watchingDoneCtx, which is renewed once StopWatching has stopped all watches
+	watchMutex      sync.Mutex
+	watchingDoneCtx context.Context
+	watchingDone    context.CancelFunc
+	watchingWait    sync.WaitGroup
+}
+
+// NewFileClient creates a new File Client which stores the configuration key tree under config.Path.
+// The store is only safe for use by a single writing process.
+func NewFileClient(config types.ServiceConfig) (*fileClient, error) {
+	if config.Path == "" {
+		return nil, errors.New("unable to create File Client: configuration path not set")
+	}
+
+	store, err := newStore(config.Path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create File Client: %w", err)
+	}
+
+	client := fileClient{
+		location:       config.Path,
+		store:          store,
+		configBasePath: strings.Trim(config.BasePath, kvtree.KeyDelimiter),
+		pollInterval:   config.PollInterval,
+	}
+
+	if client.pollInterval <= 0 {
+		client.pollInterval = defaultPollInterval
+	}
+
+	client.watchingDoneCtx, client.watchingDone = context.WithCancel(context.Background())
+
+	return &client, nil
+}
+
+// fullPath returns the path of the key name under the base path, which name must not escape, e.g. with "../"
+func (client *fileClient) fullPath(name string) (string, error) {
+	fullPath := path.Join(client.configBasePath, name)
+	if fullPath == ".." || strings.HasPrefix(fullPath, "../") || !kvtree.HasPrefix(fullPath, client.configBasePath) {
+		return "", fmt.Errorf("configuration key %s is outside of the base path %s", name, client.configBasePath)
+	}
+
+	return fullPath, nil
+}
+
+// IsAlive checks if the location of the configuration store is available
+func (client *fileClient) IsAlive() bool {
+	return client.store.exists()
+}
+
+// HasConfiguration checks to see if the store contains the service's configuration.
+func (client *fileClient) HasConfiguration() (bool, error) {
+	return client.hasKeys(client.configBasePath)
+}
+
+// HasSubConfiguration checks to see if the store contains the service's sub configuration.
+func (client *fileClient) HasSubConfiguration(name string) (bool, error) {
+	fullPath, err := client.fullPath(name)
+	if err != nil {
+		return false, err
+	}
+
+	return client.hasKeys(fullPath)
+}
+
+func (client *fileClient) hasKeys(prefix string) (bool, error) {
+	pairs, err := client.load()
+	if err != nil {
+		return false, fmt.Errorf("checking configuration existence from %s failed: %v", client.location, err)
+	}
+
+	for key := range pairs {
+		if kvtree.HasPrefix(key, prefix) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// PutConfigurationMap puts a full configuration map into the store.
+// The sub-paths to where the values are to be stored are generated from the map key.
+func (client *fileClient) PutConfigurationMap(configuration map[string]any, overwrite bool) error {
+	return client.update(func(pairs map[string]string) error {
+		for key, value := range kvtree.Flatten("", configuration) {
+			keyPath, err := client.fullPath(key)
+			if err != nil {
+				return err
+			}
+			if _, exists := pairs[keyPath]; !exists || overwrite {
+				pairs[keyPath] = value
+			}
+		}
+		return nil
+	})
+}
+
+// PutConfiguration puts a full configuration struct into the store
+func (client *fileClient) PutConfiguration(configuration interface{}, overwrite bool) error {
+	configMap := make(map[string]any)
+	bytes, err := json.Marshal(configuration)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(bytes, &configMap)
+	if err != nil {
+		return err
+	}
+
+	return client.PutConfigurationMap(configMap, overwrite)
+}
+
+// GetConfiguration gets the full configuration from the store into the target configuration struct.
+// Returns the configuration in the target struct as interface{}, which caller must cast
+func (client *fileClient) GetConfiguration(configStruct interface{}) (interface{}, error) {
+	pairs, err := client.load()
+	if err != nil {
+		return nil, err
+	}
+
+	subtree := kvtree.Subtree(client.configBasePath, pairs)
+	if len(subtree) == 0 {
+		return nil, fmt.Errorf("the Configuration service (%s) doesn't contain configuration for %s", client.location, client.configBasePath)
+	}
+
+	if err := kvtree.Decode(client.configBasePath, subtree, configStruct); err != nil {
+		return nil, err
+	}
+
+	return configStruct, nil
+}
+
+// WatchForChanges polls the store for changes to the target key and sends back updates on the update channel.
+// The current configuration is sent once the watch has started.
+// Sends the configuration in the target struct as interface{} on updateChannel, which caller must cast
+func (client *fileClient) WatchForChanges(updateChannel chan<- interface{}, errorChannel chan<- error, configuration interface{}, watchKey string, _ messaging.MessageClient) {
+	err := client.watchForChanges(context.Background(), updateChannel, errorChannel, configuration, watchKey, func(error) {})
+	if err != nil {
+		// the error is sent from a goroutine, so that the caller isn't blocked until it reads from its channels
+		done, watchDone := client.startWatch(context.Background())
+		go func() {
+			defer watchDone()
+			sendError(done, errorChannel, err)
+		}()
+	}
+}
+
+// Watch sets up a watch for the target key like WatchForChanges and returns the handle to stop it,
+// independently of the other watches of the client. The watch also stops once ctx is done.
+func (client *fileClient) Watch(ctx context.Context, updateChannel chan<- interface{}, errorChannel chan<- error, configuration interface{}, watchKey string, _ messaging.MessageClient) types.Watcher {
+	watcher, ctx := watch.New(ctx)
+	err := client.watchForChanges(ctx, updateChannel, errorChannel, configuration, watchKey, watcher.Finish)
+	if err != nil {
+		watcher.Finish(err)
+	}
+	return watcher
+}
+
+// watchForChanges polls the store for changes to the target key until either ctx is done or StopWatching is called,
+// and calls exited with the reason once it has stopped. An error is returned if the watch can't be set up.
+func (client *fileClient) watchForChanges(ctx context.Context, updateChannel chan<- interface{}, errorChannel chan<- error, configuration interface{}, watchKey string, exited func(error)) error {
+	prefix, err := client.fullPath(watchKey)
+	if err != nil {
+		return err
+	}
+
+	done, watchDone := client.startWatch(ctx)
+	go func() {
+		defer watchDone()
+		defer func() { exited(done.Err()) }()
+
+		ticker := time.NewTicker(client.pollInterval)
+		defer ticker.Stop()
+
+		var previous map[string]string
+		for {
+			pairs, err := client.load()
+			if err != nil {
+				if !sendError(done, errorChannel, err) {
+					return
+				}
+			} else if subtree := kvtree.Subtree(prefix, pairs); previous == nil || !maps.Equal(previous, subtree) {
+				previous = subtree
+				if err := kvtree.Decode(prefix, subtree, configuration); err != nil {
+					if !sendError(done, errorChannel, err) {
+						return
+					}
+				} else if !sendUpdate(done, updateChannel, configuration) {
+					return
+				}
+			}
+
+			select {
+			case <-done.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return nil
+}
+
+// sendUpdate delivers the update unless watching is stopped first, in which case false is returned
+func sendUpdate(done context.Context, updateChannel chan<- interface{}, update interface{}) bool {
+	select {
+	case <-done.Done():
+		return false
+	case updateChannel <- update:
+		return true
+	}
+}
+
+// sendError delivers the error unless watching is stopped first, in which case false is returned
+func sendError(done context.Context, errorChannel chan<- error, err error) bool {
+	select {
+	case <-done.Done():
+		return false
+	case errorChannel <- err:
+		return true
+	}
+}
+
+// StopWatching causes all WatchForChanges processing to stop and waits until they have exited.
+func (client *fileClient) StopWatching() {
+	client.watchMutex.Lock()
+	defer client.watchMutex.Unlock()
+
+	client.watchingDone()
+	client.watchingWait.Wait()
+
+	client.watchingDoneCtx, client.watchingDone = context.WithCancel(context.Background())
+}
+
+// startWatch tracks a new watch and returns its context, which is done once either ctx is done or StopWatching is
+// called. The returned function must be called once the watch has exited.
+func (client *fileClient) startWatch(ctx context.Context) (context.Context, func()) {
+	client.watchMutex.Lock()
+	defer client.watchMutex.Unlock()
+
+	client.watchingWait.Add(1)
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(client.watchingDoneCtx, cancel)
+
+	return ctx, func() {
+		stop()
+		cancel()
+		client.watchingWait.Done()
+	}
+}
+
+// ConfigurationValueExists checks if a configuration value exists in the store
+func (client *fileClient) ConfigurationValueExists(name string) (bool, error) {
+	fullPath, err := client.fullPath(name)
+	if err != nil {
+		return false, err
+	}
+
+	pairs, err := client.load()
+	if err != nil {
+		return false, fmt.Errorf("unable to check existence of %s in %s: %v", fullPath, client.location, err)
+	}
+
+	_, exists := pairs[fullPath]
+	return exists, nil
+}
+
+// GetConfigurationValue gets a specific configuration value from the store
+func (client *fileClient) GetConfigurationValue(name string) ([]byte, error) {
+	fullPath, err := client.fullPath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetConfigurationValueByFullPath(fullPath)
+}
+
+// GetConfigurationValueByFullPath gets a specific configuration value given the full path from the store
+func (client *fileClient) GetConfigurationValueByFullPath(fullPath string) ([]byte, error) {
+	pairs, err := client.load()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get value for %s from %s: %v", fullPath, client.location, err)
+	}
+
+	value, exists := pairs[strings.Trim(fullPath, kvtree.KeyDelimiter)]
+	if !exists {
+		return nil, nil
+	}
+
+	return []byte(value), nil
+}
+
+// PutConfigurationValue puts a specific configuration value into the store
+func (client *fileClient) PutConfigurationValue(name string, value []byte) error {
+	fullPath, err := client.fullPath(name)
+	if err != nil {
+		return err
+	}
+
+	return client.update(func(pairs map[string]string) error {
+		pairs[fullPath] = string(value)
+		return nil
+	})
+}
+
+// GetConfigurationKeys returns the full path of all keys under name
+func (client *fileClient) GetConfigurationKeys(name string) ([]string, error) {
+	fullPath, err := client.fullPath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs, err := client.load()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get list of keys for %s from %s: %v", fullPath, client.location, err)
+	}
+
+	subtree := kvtree.Subtree(fullPath, pairs)
+	if len(subtree) == 0 {
+		return nil, nil
+	}
+
+	return kvtree.SortedKeys(subtree), nil
+}
+
+// DeleteConfigurationValue removes a specific configuration value from the store
+func (client *fileClient) DeleteConfigurationValue(name string) error {
+	fullPath, err := client.fullPath(name)
+	if err != nil {
+		return err
+	}
+
+	return client.update(func(pairs map[string]string) error {
+		delete(pairs, fullPath)
+		return nil
+	})
+}
+
+// DeleteSubConfiguration removes all configuration values located under name from the store
+func (client *fileClient) DeleteSubConfiguration(name string) error {
+	fullPath, err := client.fullPath(name)
+	if err != nil {
+		return err
+	}
+
+	return client.update(func(pairs map[string]string) error {
+		for key := range kvtree.Subtree(fullPath, pairs) {
+			delete(pairs, key)
+		}
+		return nil
+	})
+}
+
+func (client *fileClient) load() (map[string]string, error) {
+	client.storeMutex.Lock()
+	defer client.storeMutex.Unlock()
+
+	return client.store.load()
+}
+
+// update applies the changes to the key tree and saves the result as a single operation
+func (client *fileClient) update(apply func(pairs map[string]string) error) error {
+	client.storeMutex.Lock()
+	defer client.storeMutex.Unlock()
+
+	pairs, err := client.store.load()
+	if err != nil {
+		return err
+	}
+
+	if err := apply(pairs); err != nil {
+		return err
+	}
+
+	return client.store.save(pairs)
+}
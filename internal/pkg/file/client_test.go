@@ -0,0 +1,381 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-configuration/v3/pkg/types"
+)
+
+const basePath = "edgex/v3/core-data"
+
+type LoggingInfo struct {
+	EnableRemote bool
+	File         string
+}
+
+type TestConfig struct {
+	Logging  LoggingInfo
+	Port     int
+	Host     string
+	LogLevel string
+	Temp     float64
+}
+
+var expectedConfig = TestConfig{
+	Logging: LoggingInfo{
+		EnableRemote: true,
+		File:         "NONE",
+	},
+	Port:     8000,
+	Host:     "localhost",
+	LogLevel: "debug",
+	Temp:     36.123456,
+}
+
+// storeLocations returns a location for each of the supported store formats
+func storeLocations(t *testing.T) map[string]string {
+	dir := t.TempDir()
+	return map[string]string{
+		"Directory": filepath.Join(dir, "config"),
+		"JSON":      filepath.Join(dir, "config.json"),
+		"YAML":      filepath.Join(dir, "config.yaml"),
+		"TOML":      filepath.Join(dir, "config.toml"),
+	}
+}
+
+func makeFileClient(t *testing.T, location string) *fileClient {
+	config := types.ServiceConfig{
+		Type:         "file",
+		Path:         location,
+		BasePath:     basePath,
+		PollInterval: 10 * time.Millisecond,
+	}
+
+	client, err := NewFileClient(config)
+	require.NoError(t, err)
+	return client
+}
+
+func TestNewFileClientNoPath(t *testing.T) {
+	_, err := NewFileClient(types.ServiceConfig{Type: "file"})
+	require.Error(t, err)
+}
+
+func TestNewFileClientUnsupportedExtension(t *testing.T) {
+	location := filepath.Join(t.TempDir(), "config.ini")
+	_, err := NewFileClient(types.ServiceConfig{Type: "file", Path: location})
+	require.Error(t, err)
+	assert.NoFileExists(t, location)
+}
+
+func TestIsAlive(t *testing.T) {
+	client := makeFileClient(t, filepath.Join(t.TempDir(), "config.yaml"))
+	assert.True(t, client.IsAlive())
+
+	client = makeFileClient(t, filepath.Join(t.TempDir(), "missing", "config.yaml"))
+	assert.False(t, client.IsAlive())
+}
+
+func TestConfigurationRoundTrip(t *testing.T) {
+	for name, location := range storeLocations(t) {
+		t.Run(name, func(t *testing.T) {
+			client := makeFileClient(t, location)
+
+			exists, err := client.HasConfiguration()
+			require.NoError(t, err)
+			require.False(t, exists)
+
+			err = client.PutConfiguration(expectedConfig, true)
+			require.NoError(t, err)
+
+			exists, err = client.HasConfiguration()
+			require.NoError(t, err)
+			require.True(t, exists)
+
+			exists, err = client.HasSubConfiguration("Logging")
+			require.NoError(t, err)
+			assert.True(t, exists)
+
+			exists, err = client.HasSubConfiguration("Log")
+			require.NoError(t, err)
+			assert.False(t, exists)
+
+			// a new client must load what was stored by the previous one
+			result, err := makeFileClient(t, location).GetConfiguration(&TestConfig{})
+			require.NoError(t, err)
+			assert.Equal(t, expectedConfig, *result.(*TestConfig))
+		})
+	}
+}
+
+func TestPutConfigurationMap(t *testing.T) {
+	for name, location := range storeLocations(t) {
+		t.Run(name, func(t *testing.T) {
+			client := makeFileClient(t, location)
+
+			configMap := map[string]any{
+				"int":        1,
+				"string":     "hello",
+				"nestedNode": map[string]any{"field1": "value1", "field2": "value2"},
+			}
+			require.NoError(t, client.PutConfigurationMap(configMap, false))
+
+			configMap["nestedNode"] = map[string]any{"field1": "overwrite1"}
+			require.NoError(t, client.PutConfigurationMap(configMap, false))
+
+			actual, err := client.GetConfigurationValue("nestedNode/field1")
+			require.NoError(t, err)
+			assert.Equal(t, "value1", string(actual))
+
+			require.NoError(t, client.PutConfigurationMap(configMap, true))
+
+			actual, err = client.GetConfigurationValue("nestedNode/field1")
+			require.NoError(t, err)
+			assert.Equal(t, "overwrite1", string(actual))
+
+			keys, err := client.GetConfigurationKeys("nestedNode")
+			require.NoError(t, err)
+			assert.Equal(t, []string{basePath + "/nestedNode/field1", basePath + "/nestedNode/field2"}, keys)
+		})
+	}
+}
+
+func TestConfigurationValue(t *testing.T) {
+	for name, location := range storeLocations(t) {
+		t.Run(name, func(t *testing.T) {
+			client := makeFileClient(t, location)
+
+			exists, err := client.ConfigurationValueExists("Foo")
+			require.NoError(t, err)
+			require.False(t, exists)
+
+			actual, err := client.GetConfigurationValue("Foo")
+			require.NoError(t, err)
+			require.Nil(t, actual)
+
+			require.NoError(t, client.PutConfigurationValue("Foo", []byte("bar")))
+
+			exists, err = client.ConfigurationValueExists("Foo")
+			require.NoError(t, err)
+			require.True(t, exists)
+
+			actual, err = client.GetConfigurationValue("Foo")
+			require.NoError(t, err)
+			assert.Equal(t, []byte("bar"), actual)
+
+			actual, err = client.GetConfigurationValueByFullPath(basePath + "/Foo")
+			require.NoError(t, err)
+			assert.Equal(t, []byte("bar"), actual)
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	for name, location := range storeLocations(t) {
+		t.Run(name, func(t *testing.T) {
+			client := makeFileClient(t, location)
+
+			require.NoError(t, client.PutConfigurationValue("Foo", []byte("bar")))
+			require.NoError(t, client.PutConfigurationValue("Writable/LogLevel", []byte("INFO")))
+			require.NoError(t, client.PutConfigurationValue("Writable/InsecureSecrets/DB/Path", []byte("redisdb")))
+			require.NoError(t, client.PutConfigurationValue("WritableExtra", []byte("true")))
+
+			require.NoError(t, client.DeleteConfigurationValue("Foo"))
+			require.NoError(t, client.DeleteConfigurationValue("Foo"))
+			require.NoError(t, client.DeleteSubConfiguration("Writable"))
+
+			keys, err := client.GetConfigurationKeys("")
+			require.NoError(t, err)
+			assert.Equal(t, []string{basePath + "/WritableExtra"}, keys)
+		})
+	}
+}
+
+func TestDirectoryLayout(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "config")
+	client := makeFileClient(t, root)
+
+	require.NoError(t, client.PutConfigurationValue("Writable/LogLevel", []byte("INFO")))
+
+	contents, err := os.ReadFile(filepath.Join(root, basePath, "Writable", "LogLevel"))
+	require.NoError(t, err)
+	assert.Equal(t, "INFO", string(contents))
+}
+
+func TestKeyOutsideOfBasePath(t *testing.T) {
+	for name, location := range storeLocations(t) {
+		t.Run(name, func(t *testing.T) {
+			client := makeFileClient(t, location)
+
+			for _, key := range []string{"../../escaped", "../../../../../escaped", "Writable/../../sibling"} {
+				require.Error(t, client.PutConfigurationValue(key, []byte("value")), key)
+				require.Error(t, client.PutConfigurationMap(map[string]any{key: "value"}, true), key)
+				_, err := client.GetConfigurationValue(key)
+				require.Error(t, err, key)
+				require.Error(t, client.DeleteConfigurationValue(key), key)
+			}
+
+			// keys which stay under the base path once cleaned are accepted
+			require.NoError(t, client.PutConfigurationValue("Writable/../Foo", []byte("bar")))
+			actual, err := client.GetConfigurationValue("Foo")
+			require.NoError(t, err)
+			assert.Equal(t, []byte("bar"), actual)
+
+			keys, err := client.GetConfigurationKeys("")
+			require.NoError(t, err)
+			assert.Equal(t, []string{basePath + "/Foo"}, keys)
+		})
+	}
+}
+
+func TestDirectoryKeyOutsideOfRoot(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "config")
+
+	// without base path, the key can only be checked against the root directory
+	client, err := NewFileClient(types.ServiceConfig{Type: "file", Path: root})
+	require.NoError(t, err)
+
+	require.Error(t, client.PutConfigurationValue("../escaped", []byte("value")))
+	assert.NoFileExists(t, filepath.Join(dir, "escaped"))
+
+	_, err = client.store.(*directoryStore).keyPath("../escaped")
+	require.Error(t, err)
+}
+
+func TestWatchForChanges(t *testing.T) {
+	for name, location := range storeLocations(t) {
+		t.Run(name, func(t *testing.T) {
+			client := makeFileClient(t, location)
+			defer client.StopWatching()
+
+			require.NoError(t, client.PutConfiguration(expectedConfig, true))
+
+			updates := make(chan interface{})
+			errs := make(chan error)
+			client.WatchForChanges(updates, errs, &LoggingInfo{}, "Logging", nil)
+
+			// the current configuration is sent when the watch starts
+			initial := receiveUpdate(t, updates, errs)
+			assert.Equal(t, "NONE", initial.File)
+
+			// simulate an edit of the store by another client
+			require.NoError(t, makeFileClient(t, location).PutConfigurationValue("Logging/File", []byte("log.txt")))
+
+			updated := receiveUpdate(t, updates, errs)
+			assert.Equal(t, "log.txt", updated.File)
+			assert.True(t, updated.EnableRemote)
+		})
+	}
+}
+
+func TestStopWatching(t *testing.T) {
+	client := makeFileClient(t, filepath.Join(t.TempDir(), "config.json"))
+
+	updates := make(chan interface{})
+	errs := make(chan error)
+	client.WatchForChanges(updates, errs, &LoggingInfo{}, "Logging", nil)
+	client.WatchForChanges(updates, errs, &TestConfig{}, "", nil)
+
+	stopped := make(chan struct{})
+	go func() {
+		client.StopWatching()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("StopWatching did not return")
+	}
+}
+
+func TestWatchForChangesAfterStopWatching(t *testing.T) {
+	client := makeFileClient(t, filepath.Join(t.TempDir(), "config.json"))
+	require.NoError(t, client.PutConfiguration(expectedConfig, true))
+
+	updates := make(chan interface{})
+	errs := make(chan error)
+	client.WatchForChanges(updates, errs, &LoggingInfo{}, "Logging", nil)
+	client.StopWatching()
+
+	// the watches started after StopWatching run until it is called again
+	client.WatchForChanges(updates, errs, &LoggingInfo{}, "Logging", nil)
+	defer client.StopWatching()
+
+	assert.Equal(t, "NONE", receiveUpdate(t, updates, errs).File)
+}
+
+func TestWatchForChangesKeyOutsideOfBasePath(t *testing.T) {
+	client := makeFileClient(t, filepath.Join(t.TempDir(), "config.json"))
+	defer client.StopWatching()
+
+	// the error is sent once the caller reads from the channels
+	updates := make(chan interface{})
+	errs := make(chan error)
+	client.WatchForChanges(updates, errs, &LoggingInfo{}, "../escape", nil)
+
+	select {
+	case err := <-errs:
+		require.Error(t, err)
+	case <-updates:
+		t.Fatal("unexpected update")
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for error")
+	}
+}
+
+func TestWatch(t *testing.T) {
+	client := makeFileClient(t, filepath.Join(t.TempDir(), "config.json"))
+	defer client.StopWatching()
+
+	require.NoError(t, client.PutConfiguration(expectedConfig, true))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := make(chan interface{})
+	errs := make(chan error)
+	watcher := client.Watch(ctx, updates, errs, &LoggingInfo{}, "Logging", nil)
+	assert.Equal(t, "NONE", receiveUpdate(t, updates, errs).File)
+
+	// the watch stops along with its context
+	cancel()
+	select {
+	case <-watcher.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the watch to stop")
+	}
+	assert.ErrorIs(t, watcher.Err(), context.Canceled)
+
+	t.Run("Key outside of base path", func(t *testing.T) {
+		watcher := client.Watch(context.Background(), updates, errs, &LoggingInfo{}, "../escape", nil)
+		<-watcher.Done()
+		require.Error(t, watcher.Err())
+		assert.NotErrorIs(t, watcher.Err(), context.Canceled)
+	})
+}
+
+func receiveUpdate(t *testing.T, updates chan interface{}, errs chan error) *LoggingInfo {
+	select {
+	case raw := <-updates:
+		actual, ok := raw.(*LoggingInfo)
+		require.True(t, ok, "unexpected update type")
+		return actual
+	case err := <-errs:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for update")
+	}
+	return nil
+}
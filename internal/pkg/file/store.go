@@ -0,0 +1,240 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package file
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/kvtree"
+)
+
+const tempFilePattern = ".tmp-*"
+
+// store persists the flattened configuration key tree
+type store interface {
+	// load reads the complete key tree
+	load() (map[string]string, error)
+	// save replaces the complete key tree
+	save(pairs map[string]string) error
+	// exists checks if the location of the store is available
+	exists() bool
+}
+
+// newStore creates the store for the location. Locations with a .json, .yaml, .yml or .toml extension are stored as
+// a single document, locations without extension are a directory in which each key is stored as a file.
+func newStore(location string) (store, error) {
+	switch extension := strings.ToLower(filepath.Ext(location)); extension {
+	case ".json":
+		return &documentStore{path: location, marshal: marshalJSON, unmarshal: json.Unmarshal}, nil
+	case ".yaml", ".yml":
+		return &documentStore{path: location, marshal: yaml.Marshal, unmarshal: yaml.Unmarshal}, nil
+	case ".toml":
+		return &documentStore{path: location, marshal: toml.Marshal, unmarshal: toml.Unmarshal}, nil
+	case "":
+		return &directoryStore{root: location}, nil
+	default:
+		return nil, fmt.Errorf("unsupported configuration file extension '%s', expected .json, .yaml, .yml, .toml or none for a directory", extension)
+	}
+}
+
+// directoryStore stores each key as a file, relative to the root directory, containing the raw value
+type directoryStore struct {
+	root string
+}
+
+func (s *directoryStore) load() (map[string]string, error) {
+	pairs := make(map[string]string)
+	if !s.exists() {
+		return pairs, nil
+	}
+
+	err := filepath.WalkDir(s.root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() || isTempFile(entry.Name()) {
+			return nil
+		}
+
+		value, err := os.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+
+		key, err := filepath.Rel(s.root, filePath)
+		if err != nil {
+			return err
+		}
+
+		pairs[filepath.ToSlash(key)] = string(value)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read configuration from directory %s: %v", s.root, err)
+	}
+
+	return pairs, nil
+}
+
+func (s *directoryStore) save(pairs map[string]string) error {
+	current, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	for key := range current {
+		if _, keep := pairs[key]; keep {
+			continue
+		}
+		if err := s.remove(key); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range kvtree.SortedKeys(pairs) {
+		if existing, found := current[key]; found && existing == pairs[key] {
+			continue
+		}
+		filePath, err := s.keyPath(key)
+		if err != nil {
+			return err
+		}
+		if err := writeFileAtomic(filePath, []byte(pairs[key])); err != nil {
+			return fmt.Errorf("unable to write configuration value for %s: %v", key, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *directoryStore) exists() bool {
+	info, err := os.Stat(s.root)
+	return err == nil && info.IsDir()
+}
+
+// keyPath returns the path of the file for the key, which must be located under the root
+func (s *directoryStore) keyPath(key string) (string, error) {
+	filePath := filepath.Join(s.root, filepath.FromSlash(key))
+	relative, err := filepath.Rel(s.root, filePath)
+	if err != nil || relative == "." || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("configuration key %s is outside of directory %s", key, s.root)
+	}
+
+	return filePath, nil
+}
+
+// remove deletes the file for the key along with any directories left empty up to the root
+func (s *directoryStore) remove(key string) error {
+	filePath, err := s.keyPath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("unable to remove configuration value for %s: %v", key, err)
+	}
+
+	root := filepath.Clean(s.root)
+	for dir := filepath.Dir(filePath); dir != root && strings.HasPrefix(dir, root); dir = filepath.Dir(dir) {
+		if err := os.Remove(dir); err != nil {
+			// directory not empty
+			break
+		}
+	}
+
+	return nil
+}
+
+// documentStore stores the key tree as a single nested document
+type documentStore struct {
+	path      string
+	marshal   func(any) ([]byte, error)
+	unmarshal func([]byte, any) error
+}
+
+func (s *documentStore) load() (map[string]string, error) {
+	contents, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return make(map[string]string), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read configuration document %s: %v", s.path, err)
+	}
+
+	document := make(map[string]any)
+	if err := s.unmarshal(contents, &document); err != nil {
+		return nil, fmt.Errorf("unable to parse configuration document %s: %v", s.path, err)
+	}
+
+	return kvtree.Flatten("", document), nil
+}
+
+func (s *documentStore) save(pairs map[string]string) error {
+	document, err := kvtree.Expand("", pairs)
+	if err != nil {
+		return err
+	}
+
+	contents, err := s.marshal(document)
+	if err != nil {
+		return fmt.Errorf("unable to encode configuration document %s: %v", s.path, err)
+	}
+
+	if err := writeFileAtomic(s.path, contents); err != nil {
+		return fmt.Errorf("unable to write configuration document %s: %v", s.path, err)
+	}
+
+	return nil
+}
+
+func (s *documentStore) exists() bool {
+	info, err := os.Stat(filepath.Dir(s.path))
+	return err == nil && info.IsDir()
+}
+
+func marshalJSON(document any) ([]byte, error) {
+	return json.MarshalIndent(document, "", "  ")
+}
+
+// writeFileAtomic writes the contents to a temporary file which then replaces the target file,
+// so a reader never sees a partially written file.
+func writeFileAtomic(filePath string, contents []byte) error {
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+
+	tempFile, err := os.CreateTemp(dir, tempFilePattern)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tempFile.Name())
+	}()
+
+	if _, err := tempFile.Write(contents); err != nil {
+		_ = tempFile.Close()
+		return err
+	}
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tempFile.Name(), filePath)
+}
+
+func isTempFile(name string) bool {
+	matched, _ := filepath.Match(tempFilePattern, name)
+	return matched
+}
@@ -6,29 +6,33 @@
 package api
 
 import (
-	"errors"
+	"context"
+	nethttp "net/http"
 
 	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/keeper/utils/http"
 )
 
 type Caller struct {
-	baseUrl string
+	baseUrl    string
+	httpClient *nethttp.Client
 }
 
-// NewCaller creates an instance of Caller
-func NewCaller(baseUrl string) *Caller {
+// NewCaller creates an instance of Caller making its requests with httpClient
+func NewCaller(baseUrl string, httpClient *nethttp.Client) *Caller {
 	return &Caller{
-		baseUrl: baseUrl,
+		baseUrl:    baseUrl,
+		httpClient: httpClient,
 	}
 }
 
-func (c *Caller) Ping() error {
-	errResp, err := http.GetRequest(nil, c.baseUrl, ApiPingRoute, nil)
+// Ping checks if Core Keeper is reachable
+func (c *Caller) Ping(ctx context.Context) error {
+	errResp, err := http.GetRequest(ctx, c.httpClient, nil, c.baseUrl, ApiPingRoute, nil)
 	if err != nil {
 		return err
 	}
 	if errResp.StatusCode != 0 {
-		return errors.New(errResp.Message)
+		return errResp
 	}
 	return nil
 }
@@ -6,7 +6,7 @@
 package api
 
 import (
-	"errors"
+	"context"
 	"net/http"
 	"net/url"
 	"path"
@@ -27,27 +27,27 @@ func (c *Caller) KV() *KV {
 
 // Get is used to lookup a single key. The returned pointer
 // to the KVPair will be nil if the key does not exist.
-func (k *KV) Get(key string) (res dtos.MultiKVResponse, err error) {
+func (k *KV) Get(ctx context.Context, key string) (res dtos.MultiKVResponse, err error) {
 	pathParams := url.Values{}
 	pathParams.Add(Plaintext, "true")
 
 	url := path.Join(ApiKVRoute, key)
-	errResp, err := httpUtils.GetRequest(&res, k.c.baseUrl, url, pathParams)
+	errResp, err := httpUtils.GetRequest(ctx, k.c.httpClient, &res, k.c.baseUrl, url, pathParams)
 	if err != nil {
 		return res, err
 	}
 	if errResp.StatusCode != 0 {
-		return res, errors.New(errResp.Message)
+		return res, errResp
 	}
 	return res, nil
 }
 
-func (k *KV) Keys(key string) (res dtos.MultiKeyResponse, err error) {
+func (k *KV) Keys(ctx context.Context, key string) (res dtos.MultiKeyResponse, err error) {
 	pathParams := url.Values{}
 	pathParams.Add(KeyOnly, "true")
 
 	url := path.Join(ApiKVRoute, key)
-	errResp, err := httpUtils.GetRequest(&res, k.c.baseUrl, url, pathParams)
+	errResp, err := httpUtils.GetRequest(ctx, k.c.httpClient, &res, k.c.baseUrl, url, pathParams)
 	if err != nil {
 		return res, err
 	}
@@ -55,13 +55,13 @@ func (k *KV) Keys(key string) (res dtos.MultiKeyResponse, err error) {
 		return res, nil
 	}
 	if errResp.StatusCode != 0 {
-		return res, errors.New(errResp.Message)
+		return res, errResp
 	}
 	return res, nil
 }
 
 // Put create/update a single key with value
-func (k *KV) Put(key string, data interface{}) error {
+func (k *KV) Put(ctx context.Context, key string, data interface{}) error {
 	keyPath := path.Join(ApiKVRoute, key)
 
 	value := data
@@ -71,18 +71,18 @@ func (k *KV) Put(key string, data interface{}) error {
 	request := dtos.AddKeysRequest{
 		Value: value,
 	}
-	errResp, err := httpUtils.PutRequest(nil, k.c.baseUrl, keyPath, nil, request)
+	errResp, err := httpUtils.PutRequest(ctx, k.c.httpClient, nil, k.c.baseUrl, keyPath, nil, request)
 	if err != nil {
 		return err
 	}
 	if errResp.StatusCode != 0 {
-		return errors.New(errResp.Message)
+		return errResp
 	}
 	return nil
 }
 
 // PutKeys create/update all keys under a prefix with value
-func (k *KV) PutKeys(key string, data interface{}) error {
+func (k *KV) PutKeys(ctx context.Context, key string, data interface{}) error {
 	keyPath := path.Join(ApiKVRoute, key)
 	urlParams := url.Values{}
 	urlParams.Add(Flatten, "true")
@@ -93,28 +93,48 @@ func (k *KV) PutKeys(key string, data interface{}) error {
 	request := dtos.AddKeysRequest{
 		Value: value,
 	}
-	errResp, err := httpUtils.PutRequest(nil, k.c.baseUrl, keyPath, urlParams, request)
+	errResp, err := httpUtils.PutRequest(ctx, k.c.httpClient, nil, k.c.baseUrl, keyPath, urlParams, request)
 	if err != nil {
 		return err
 	}
 	if errResp.StatusCode != 0 {
-		return errors.New(errResp.Message)
+		return errResp
+	}
+	return nil
+}
+
+// Delete deletes a single key
+func (k *KV) Delete(ctx context.Context, key string) error {
+	keyPath := path.Join(ApiKVRoute, key)
+
+	errResp, err := httpUtils.DeleteRequest(ctx, k.c.httpClient, nil, k.c.baseUrl, keyPath, nil)
+	if err != nil {
+		return err
+	}
+	if errResp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if errResp.StatusCode != 0 {
+		return errResp
 	}
 	return nil
 }
 
 // DeleteKeys delete all keys under a prefix with value
-func (k *KV) DeleteKeys(key string) error {
+func (k *KV) DeleteKeys(ctx context.Context, key string) error {
 	keyPath := path.Join(ApiKVRoute, key)
 	urlParams := url.Values{}
 	urlParams.Add(PrefixMatch, "true")
 
-	errResp, err := httpUtils.DeleteRequest(nil, k.c.baseUrl, keyPath, urlParams)
+	errResp, err := httpUtils.DeleteRequest(ctx, k.c.httpClient, nil, k.c.baseUrl, keyPath, urlParams)
 	if err != nil {
 		return err
 	}
+	if errResp.StatusCode == http.StatusNotFound {
+		return nil
+	}
 	if errResp.StatusCode != 0 {
-		return errors.New(errResp.Message)
+		return errResp
 	}
 	return nil
 }
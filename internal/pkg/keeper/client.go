@@ -6,16 +6,31 @@
 package keeper
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
+	"maps"
+	nethttp "net/http"
 	"path"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/spf13/cast"
 
 	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/keeper/api"
 	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/keeper/dtos"
+	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/keeper/models"
 	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/keeper/utils/http"
+	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/kvtree"
+	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/retry"
+	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/tlsconfig"
+	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/token"
+	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/watch"
 	"github.com/edgexfoundry/go-mod-configuration/v3/pkg/types"
 
 	"github.com/edgexfoundry/go-mod-messaging/v3/messaging"
@@ -24,25 +39,79 @@ import (
 
 const (
 	keeperTopicPrefix = "edgex/configs"
+	// defaultPollInterval is the interval at which the keys are read by watches without a message bus
+	defaultPollInterval = 10 * time.Second
 )
 
+// keeperClient is safe for concurrent use, an Access Token rejected by simultaneous requests is renewed once
 type keeperClient struct {
-	keeperUrl      string
-	keeperClient   *api.Caller
+	keeperUrl    string
+	keeperClient *api.Caller
+	httpClient   *nethttp.Client
+	// tokenProvider holds the Access Token sent as bearer token with every request
+	tokenProvider  *token.Provider
 	configBasePath string
-	watchingDone   chan bool
+	timeouts       types.TimeoutInfo
+	retryPolicy    types.RetryInfo
+	pollInterval   time.Duration
+	debounceWindow time.Duration
+	// messageBusInfo is the message bus used by watches which are not given a MessageClient, if configured
+	messageBusInfo   *models.MessageBusInfo
+	newMessageClient func(config msgTypes.MessageBusConfig) (messaging.MessageClient, error)
+	messageBusCount  atomic.Uint64
+	// watchMutex guards watchingDoneCtx, which is renewed once StopWatching has stopped all watches
+	watchMutex      sync.Mutex
+	watchingDoneCtx context.Context
+	watchingDone    context.CancelFunc
+	watchingWait    sync.WaitGroup
+	// casMutex serializes the compare-and-swap writes emulated by this client
+	casMutex sync.Mutex
 }
 
 // NewKeeperClient creates a new Keeper Client.
-func NewKeeperClient(config types.ServiceConfig) *keeperClient {
+// Watches which are not given a MessageClient connect to the message bus set in config.Optional, if any.
+func NewKeeperClient(config types.ServiceConfig) (*keeperClient, error) {
+	messageBusInfo, err := parseMessageBusInfo(config.Optional)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenProvider, err := token.NewProvider(config)
+	if err != nil {
+		return nil, err
+	}
+
 	client := keeperClient{
-		keeperUrl:      config.GetUrl(),
-		configBasePath: config.BasePath,
-		watchingDone:   make(chan bool, 1),
+		keeperUrl:        config.GetUrl(),
+		configBasePath:   config.BasePath,
+		timeouts:         config.Timeouts,
+		retryPolicy:      config.Retry,
+		pollInterval:     config.PollInterval,
+		debounceWindow:   config.DebounceWindow,
+		messageBusInfo:   messageBusInfo,
+		newMessageClient: messaging.NewMessageClient,
+		tokenProvider:    tokenProvider,
+	}
+
+	transport := &token.Transport{Provider: tokenProvider, Header: http.Authorization, Prefix: http.BearerPrefix}
+	if config.TLS.IsEnabled() {
+		tlsTransport, err := tlsconfig.NewTransport(config.TLS)
+		if err != nil {
+			tokenProvider.Stop()
+			return nil, fmt.Errorf("unable to create Keeper Client for %s: %w", client.keeperUrl, err)
+		}
+		transport.Base = tlsTransport
+	}
+	client.httpClient = &nethttp.Client{Transport: transport}
+
+	if client.pollInterval <= 0 {
+		client.pollInterval = defaultPollInterval
 	}
 
+	client.watchingDoneCtx, client.watchingDone = context.WithCancel(context.Background())
+
 	client.createKeeperClient(client.keeperUrl)
-	return &client
+	return &client, nil
 }
 
 func (client *keeperClient) fullPath(name string) string {
@@ -50,20 +119,102 @@ func (client *keeperClient) fullPath(name string) string {
 }
 
 func (client *keeperClient) createKeeperClient(url string) {
-	client.keeperClient = api.NewCaller(url)
+	client.keeperClient = api.NewCaller(url, client.httpClient)
+}
+
+// execute makes the request with the timeout applied to each attempt, retrying it according to the retry policy
+// on transient failures.
+func (client *keeperClient) execute(ctx context.Context, timeout time.Duration, request func(ctx context.Context) error) error {
+	return retry.Do(ctx, client.retryPolicy, isRetryable, func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		token := client.tokenProvider.Token()
+		err := request(ctx)
+		renewed, err := client.reloadAccessTokenOnAuthError(err, token)
+		if renewed {
+			// Try again with new Access Token
+			err = request(ctx)
+		}
+
+		if statusCode, isAuthError := authErrorStatus(err); isAuthError {
+			return &types.AuthError{StatusCode: statusCode, Err: err}
+		}
+		return err
+	})
+}
+
+// reloadAccessTokenOnAuthError renews the Access Token if the error of a request made with token shows it has been
+// rejected as unauthorized, and reports whether the request can be made again. The renewal is shared by all requests
+// rejected simultaneously.
+func (client *keeperClient) reloadAccessTokenOnAuthError(err error, token string) (bool, error) {
+	statusCode, isAuthError := authErrorStatus(err)
+	if !isAuthError || !client.tokenProvider.CanRenew() {
+		return false, err
+	}
+
+	if renewErr := client.tokenProvider.RenewRejected(token); renewErr != nil {
+		return false, &types.AuthError{StatusCode: statusCode, Err: renewErr}
+	}
+
+	return true, nil
+}
+
+// authErrorStatus returns the status code of the error of a Core Keeper request if it has been rejected as
+// unauthorized
+func authErrorStatus(err error) (int, bool) {
+	// an error already surfaced as AuthError isn't handled again
+	var authErr *types.AuthError
+	if errors.As(err, &authErr) {
+		return 0, false
+	}
+
+	var errResp http.ErrorResponse
+	if errors.As(err, &errResp) && (errResp.StatusCode == nethttp.StatusUnauthorized || errResp.StatusCode == nethttp.StatusForbidden) {
+		return errResp.StatusCode, true
+	}
+
+	return 0, false
+}
+
+// isRetryable checks if the error of a Core Keeper request is caused by a transient failure
+func isRetryable(err error) bool {
+	var errResp http.ErrorResponse
+	if errors.As(err, &errResp) {
+		return retry.IsRetryableStatus(errResp.StatusCode)
+	}
+
+	return retry.IsNetworkError(err)
 }
 
 // IsAlive simply checks if Core Keeper is up and running at the configured URL
 func (client *keeperClient) IsAlive() bool {
-	err := client.keeperClient.Ping()
+	return client.IsAliveCtx(context.Background())
+}
+
+// IsAliveCtx simply checks if Core Keeper is up and running at the configured URL
+func (client *keeperClient) IsAliveCtx(ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, client.timeouts.GetLiveness())
+	defer cancel()
+
+	err := client.keeperClient.Ping(ctx)
 	return err == nil
 }
 
 // HasConfiguration checks to see if Core Keeper contains the service's configuration.
 func (client *keeperClient) HasConfiguration() (bool, error) {
-	resp, err := client.keeperClient.KV().Keys(client.configBasePath)
+	return client.HasConfigurationCtx(context.Background())
+}
+
+// HasConfigurationCtx checks to see if Core Keeper contains the service's configuration.
+func (client *keeperClient) HasConfigurationCtx(ctx context.Context) (bool, error) {
+	var resp dtos.MultiKeyResponse
+	err := client.execute(ctx, client.timeouts.GetRead(), func(ctx context.Context) (err error) {
+		resp, err = client.keeperClient.KV().Keys(ctx, client.configBasePath)
+		return err
+	})
 	if err != nil {
-		return false, fmt.Errorf("checking configuration existence from Core Keeper failed: %v", err)
+		return false, fmt.Errorf("checking configuration existence from Core Keeper failed: %w", err)
 	}
 	if len(resp.Keys) == 0 {
 		return false, nil
@@ -73,10 +224,19 @@ func (client *keeperClient) HasConfiguration() (bool, error) {
 
 // HasSubConfiguration checks to see if the Configuration service contains the service's sub configuration.
 func (client *keeperClient) HasSubConfiguration(name string) (bool, error) {
+	return client.HasSubConfigurationCtx(context.Background(), name)
+}
+
+// HasSubConfigurationCtx checks to see if the Configuration service contains the service's sub configuration.
+func (client *keeperClient) HasSubConfigurationCtx(ctx context.Context, name string) (bool, error) {
 	keyPath := client.fullPath(name)
-	resp, err := client.keeperClient.KV().Keys(keyPath)
+	var resp dtos.MultiKeyResponse
+	err := client.execute(ctx, client.timeouts.GetRead(), func(ctx context.Context) (err error) {
+		resp, err = client.keeperClient.KV().Keys(ctx, keyPath)
+		return err
+	})
 	if err != nil {
-		return false, fmt.Errorf("checking configuration existence from Core Keeper failed: %v", err)
+		return false, fmt.Errorf("checking configuration existence from Core Keeper failed: %w", err)
 	}
 	if len(resp.Keys) == 0 {
 		return false, nil
@@ -87,50 +247,126 @@ func (client *keeperClient) HasSubConfiguration(name string) (bool, error) {
 // PutConfigurationMap puts a full configuration map into Core Keeper.
 // The sub-paths to where the values are to be stored in Core Keeper are generated from the map key.
 func (client *keeperClient) PutConfigurationMap(configuration map[string]any, overwrite bool) error {
+	return client.PutConfigurationMapCtx(context.Background(), configuration, overwrite)
+}
+
+// PutConfigurationMapCtx puts a full configuration map into Core Keeper.
+// The sub-paths to where the values are to be stored in Core Keeper are generated from the map key.
+func (client *keeperClient) PutConfigurationMapCtx(ctx context.Context, configuration map[string]any, overwrite bool) error {
+	_, err := client.PutConfigurationMapBatch(ctx, configuration, overwrite)
+	return err
+}
+
+// PutConfigurationMapBatch puts a full configuration map into Core Keeper with a single flattened request,
+// and reports which keys have been written, skipped or failed. Without overwrite, the existing keys are skipped.
+func (client *keeperClient) PutConfigurationMapBatch(ctx context.Context, configuration map[string]any, overwrite bool) (types.PutResult, error) {
+	return client.putBatch(ctx, configuration, overwrite)
+}
+
+func (client *keeperClient) putBatch(ctx context.Context, configuration interface{}, overwrite bool) (types.PutResult, error) {
+	var result types.PutResult
 
 	keyValues := convertInterfaceToPairs("", configuration)
+	sort.Slice(keyValues, func(i, j int) bool { return keyValues[i].Key < keyValues[j].Key })
 
-	// Put config properties into Core Keeper.
-	for _, keyValue := range keyValues {
-		exists, _ := client.ConfigurationValueExists(keyValue.Key)
-		if !exists || overwrite {
-			if err := client.PutConfigurationValue(keyValue.Key, []byte(keyValue.Value)); err != nil {
-				return err
+	var value interface{} = configuration
+	if !overwrite {
+		var resp dtos.MultiKeyResponse
+		err := client.execute(ctx, client.timeouts.GetRead(), func(ctx context.Context) (err error) {
+			resp, err = client.keeperClient.KV().Keys(ctx, client.configBasePath)
+			return err
+		})
+		if err != nil {
+			result.Failed = pairKeys(keyValues)
+			return result, fmt.Errorf("unable to get existing keys for %s from Core Keeper: %w", client.configBasePath, err)
+		}
+
+		existing := make(map[string]bool, len(resp.Keys))
+		for _, key := range resp.Keys {
+			existing[string(key)] = true
+		}
+
+		var missing []*pair
+		missingPairs := make(map[string]string)
+		for _, keyValue := range keyValues {
+			if existing[client.fullPath(keyValue.Key)] {
+				result.Skipped = append(result.Skipped, keyValue.Key)
+			} else {
+				missing = append(missing, keyValue)
+				missingPairs[keyValue.Key] = keyValue.Value
 			}
 		}
+		keyValues = missing
+
+		if len(keyValues) == 0 {
+			return result, nil
+		}
+
+		// Only the missing keys are sent, with their values as stored by PutConfigurationValue.
+		// An empty key means the configuration is a single value stored at the base path.
+		if rootValue, isRoot := missingPairs[""]; isRoot {
+			value = rootValue
+		} else if value, err = kvtree.Expand("", missingPairs); err != nil {
+			result.Failed = pairKeys(keyValues)
+			return result, fmt.Errorf("unable to put configuration into Core Keeper: %w", err)
+		}
 	}
 
-	return nil
+	err := client.execute(ctx, client.timeouts.GetWrite(), func(ctx context.Context) error {
+		return client.keeperClient.KV().PutKeys(ctx, client.configBasePath, value)
+	})
+	if err != nil {
+		result.Failed = pairKeys(keyValues)
+		return result, fmt.Errorf("unable to put configuration into Core Keeper: %w", err)
+	}
+
+	result.Written = pairKeys(keyValues)
+	return result, nil
 }
 
 // PutConfiguration puts a full configuration struct into the Configuration provider
 func (client *keeperClient) PutConfiguration(config interface{}, overwrite bool) error {
-	var err error
-	if overwrite {
-		err = client.keeperClient.KV().PutKeys(client.configBasePath, config)
-	} else {
-		kvPairs := convertInterfaceToPairs("", config)
-		for _, kv := range kvPairs {
-			exists, err := client.ConfigurationValueExists(kv.Key)
-			if err != nil {
-				return err
-			}
-			if !exists {
-				// Only create the key if not exists in core keeper
-				if err = client.PutConfigurationValue(kv.Key, []byte(kv.Value)); err != nil {
-					return err
-				}
-			}
-		}
-	}
+	return client.PutConfigurationCtx(context.Background(), config, overwrite)
+}
+
+// PutConfigurationCtx puts a full configuration struct into the Configuration provider
+func (client *keeperClient) PutConfigurationCtx(ctx context.Context, config interface{}, overwrite bool) error {
+	_, err := client.PutConfigurationBatch(ctx, config, overwrite)
 	if err != nil {
-		return fmt.Errorf("error occurred while creating/updating configuration, error: %v", err)
+		return fmt.Errorf("error occurred while creating/updating configuration, error: %w", err)
 	}
 	return nil
 }
 
+// PutConfigurationBatch puts a full configuration struct into Core Keeper and reports which keys have been written,
+// skipped or failed.
+func (client *keeperClient) PutConfigurationBatch(ctx context.Context, config interface{}, overwrite bool) (types.PutResult, error) {
+	// Convert the struct to the generic types which convertInterfaceToPairs walks through
+	var configuration interface{}
+	bytes, err := json.Marshal(config)
+	if err != nil {
+		return types.PutResult{}, err
+	}
+
+	err = json.Unmarshal(bytes, &configuration)
+	if err != nil {
+		return types.PutResult{}, err
+	}
+
+	return client.putBatch(ctx, configuration, overwrite)
+}
+
+// GetConfiguration gets the full configuration from Core Keeper into the target configuration struct.
 func (client *keeperClient) GetConfiguration(configStruct interface{}) (interface{}, error) {
-	exists, err := client.HasConfiguration()
+	return client.GetConfigurationCtx(context.Background(), configStruct)
+}
+
+// GetConfigurationCtx gets the full configuration from Core Keeper into the target configuration struct.
+func (client *keeperClient) GetConfigurationCtx(ctx context.Context, configStruct interface{}) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(ctx, client.timeouts.GetLoad())
+	defer cancel()
+
+	exists, err := client.HasConfigurationCtx(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -139,7 +375,11 @@ func (client *keeperClient) GetConfiguration(configStruct interface{}) (interfac
 		return nil, fmt.Errorf("the Configuration service (EdgeX Keeper) doesn't contain configuration for %s", client.configBasePath)
 	}
 
-	resp, err := client.keeperClient.KV().Get(client.configBasePath)
+	var resp dtos.MultiKVResponse
+	err = client.execute(ctx, client.timeouts.GetRead(), func(ctx context.Context) (err error) {
+		resp, err = client.keeperClient.KV().Get(ctx, client.configBasePath)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -151,11 +391,61 @@ func (client *keeperClient) GetConfiguration(configStruct interface{}) (interfac
 	return configStruct, nil
 }
 
+// WatchForChanges subscribes to the configuration change messages published by Core Keeper for the target key
+// and sends back updates on the update channel. Without a MessageClient, the message bus set in the optional properties
+// is used, or the keys are polled for changes if there is none. A MessageClient given by the caller is left connected
+// once the watch has stopped, as it may be shared with other watches.
 func (client *keeperClient) WatchForChanges(updateChannel chan<- interface{}, errorChannel chan<- error, configuration interface{}, waitKey string, messageBus messaging.MessageClient) {
+	client.WatchForChangesCtx(context.Background(), updateChannel, errorChannel, configuration, waitKey, messageBus)
+}
+
+// WatchForChangesCtx subscribes to the configuration change messages published by Core Keeper for the target key
+// and sends back updates on the update channel. The watch stops when either the context is done or StopWatching is called.
+func (client *keeperClient) WatchForChangesCtx(ctx context.Context, updateChannel chan<- interface{}, errorChannel chan<- error, configuration interface{}, waitKey string, messageBus messaging.MessageClient) {
+	err := client.watchForChanges(ctx, updateChannel, errorChannel, configuration, waitKey, messageBus, func(error) {})
+	if err != nil {
+		client.reportWatchError(ctx, errorChannel, err)
+	}
+}
+
+// reportWatchError sends the error of a watch which couldn't be set up from a goroutine, so that the caller isn't
+// blocked until it reads from its channels. The error is dropped once either ctx is done or StopWatching is called.
+func (client *keeperClient) reportWatchError(ctx context.Context, errorChannel chan<- error, err error) {
+	ctx, watchDone := client.startWatch(ctx)
+	go func() {
+		defer watchDone()
+		send(ctx, errorChannel, err)
+	}()
+}
+
+// Watch sets up a watch for the target key like WatchForChangesCtx and returns the handle to stop it,
+// independently of the other watches of the client.
+func (client *keeperClient) Watch(ctx context.Context, updateChannel chan<- interface{}, errorChannel chan<- error, configuration interface{}, waitKey string, messageBus messaging.MessageClient) types.Watcher {
+	watcher, ctx := watch.New(ctx)
+	err := client.watchForChanges(ctx, updateChannel, errorChannel, configuration, waitKey, messageBus, watcher.Finish)
+	if err != nil {
+		watcher.Finish(err)
+	}
+	return watcher
+}
+
+// watchForChanges subscribes to the changes of the target key until either ctx is done or StopWatching is called,
+// and calls exited with the reason once it has stopped. An error is returned if the watch can't be set up.
+func (client *keeperClient) watchForChanges(ctx context.Context, updateChannel chan<- interface{}, errorChannel chan<- error, configuration interface{}, waitKey string, messageBus messaging.MessageClient, exited func(error)) error {
+	// owned is set if the message bus has been created for this watch, rather than given by the caller
+	owned := false
 	if messageBus == nil {
-		configErr := errors.New("unable to use MessageClient to watch for configuration changes")
-		errorChannel <- configErr
-		return
+		if client.messageBusInfo == nil {
+			client.pollForChanges(ctx, updateChannel, errorChannel, configuration, waitKey, exited)
+			return nil
+		}
+
+		var err error
+		messageBus, err = client.newMessageBus(ctx)
+		if err != nil {
+			return err
+		}
+		owned = true
 	}
 
 	messages := make(chan msgTypes.MessageEnvelope)
@@ -170,28 +460,84 @@ func (client *keeperClient) WatchForChanges(updateChannel chan<- interface{}, er
 	watchErrors := make(chan error)
 	err := messageBus.Subscribe(topics, watchErrors)
 	if err != nil {
-		_ = messageBus.Disconnect()
-		errorChannel <- err
-		return
+		release(messageBus, owned)
+		return err
 	}
 
+	keyPrefix := path.Join(client.configBasePath, waitKey)
+	ctx, watchDone := client.startWatch(ctx)
+
 	go func() {
 		defer func() {
-			_ = messageBus.Disconnect()
+			exited(ctx.Err())
 		}()
+		defer watchDone()
+		defer release(messageBus, owned)
+
+		// subtree is the local copy of the watched keys, which the changes published by Core Keeper are applied to.
+		// It is nil while unknown, i.e. if the keys can't be read yet.
+		subtree, _ := client.readSubtree(ctx, keyPrefix)
+		decodePrefix := strings.TrimPrefix(keyPrefix, client.configBasePath+api.KeyDelimiter)
+
+		// reload reads the watched keys again into the subtree and reports whether they have changed
+		reload := func() (bool, error) {
+			current, err := client.readSubtree(ctx, keyPrefix)
+			if err != nil {
+				return false, err
+			}
+			changed := subtree == nil || !maps.Equal(subtree, current)
+			subtree = current
+			return changed, nil
+		}
+
+		// the changes seen within the debounce window are coalesced into a single update
+		debouncer := watch.NewDebouncer(client.debounceWindow)
+		defer debouncer.Stop()
+
+		// update decodes the subtree to configuration struct and sends it back
+		update := func() bool {
+			err := kvtree.Decode(decodePrefix, subtree, configuration)
+			if err != nil {
+				return send(ctx, errorChannel, err)
+			}
+			return send(ctx, updateChannel, configuration)
+		}
 
 		// send a nil value to updateChannel once the watcher connection is established
 		// for go-mod-bootstrap to ignore the first change event
 		// refer to the isFirstUpdate variable declared in https://github.com/edgexfoundry/go-mod-bootstrap/blob/main/bootstrap/config/config.go
-		updateChannel <- nil
+		if !send(ctx, updateChannel, nil) {
+			return
+		}
 
-	outerLoop:
 		for {
 			select {
-			case <-client.watchingDone:
+			case <-ctx.Done():
 				return
+			case <-debouncer.C():
+				debouncer.Done()
+				if !update() {
+					return
+				}
 			case e := <-watchErrors:
-				errorChannel <- e
+				if !send(ctx, errorChannel, error(&types.DegradedError{Key: waitKey, Err: e})) {
+					return
+				}
+				if !client.resubscribe(ctx, messageBus, owned, topics, watchErrors) {
+					return
+				}
+
+				// the changes published while not subscribed are lost, so read the keys again
+				changed, err := reload()
+				if err != nil {
+					if !send(ctx, errorChannel, err) {
+						return
+					}
+					continue
+				}
+				if changed && debouncer.Changed() && !update() {
+					return
+				}
 			case msgEnvelope := <-messages:
 				if msgEnvelope.ContentType != http.ContentTypeJSON {
 					continue
@@ -202,57 +548,357 @@ func (client *keeperClient) WatchForChanges(updateChannel chan<- interface{}, er
 				if err != nil {
 					continue
 				}
-				keyPrefix := path.Join(client.configBasePath, waitKey)
 
-				// get the whole configs KV DTO array from Keeper with the same keyPrefix
-				kvConfigs, err := client.keeperClient.KV().Get(keyPrefix)
-				if err != nil {
-					continue
+				// the whole subtree is only read again if the change can't be applied to the local copy
+				changed, applied := client.applyChange(subtree, keyPrefix, updatedConfig)
+				if !applied {
+					changed, err = reload()
+					if err != nil {
+						continue
+					}
+				}
+				if changed && debouncer.Changed() && !update() {
+					return
 				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// pollForChanges reads the keys under the target key at every poll interval, until either ctx is done or StopWatching
+// is called, and sends back updates on the update channel whenever they have changed.
+func (client *keeperClient) pollForChanges(ctx context.Context, updateChannel chan<- interface{}, errorChannel chan<- error, configuration interface{}, waitKey string, exited func(error)) {
+	keyPrefix := path.Join(client.configBasePath, waitKey)
+	// the keys read by readSubtree are relative to the base path
+	decodePrefix := strings.TrimPrefix(keyPrefix, client.configBasePath+api.KeyDelimiter)
 
-				// if the updated key not equal to keyPrefix, need to check the updated key and value from the message payload are valid
-				// e.g. keyPrefix = "edgex/core/2.0/core-data/Writable" which is the root level of Writable configuration
-				if updatedConfig.Key != keyPrefix {
-					foundUpdatedKey := false
-					for _, c := range kvConfigs.KVs {
-						if c.Key == updatedConfig.Key {
-							// the updated key from the message payload has been found in Keeper
-							foundUpdatedKey = true
-							// if the updated value in the message payload is different from the one obtained by Keeper
-							// skip this subscribed message payload and continue the outer loop
-							if c.Value != updatedConfig.Value {
-								continue outerLoop
-							}
-							break
-						}
+	ctx, watchDone := client.startWatch(ctx)
+
+	go func() {
+		defer func() {
+			exited(ctx.Err())
+		}()
+		defer watchDone()
+
+		ticker := time.NewTicker(client.pollInterval)
+		defer ticker.Stop()
+
+		var previous map[string]string
+		for {
+			current, err := client.readSubtree(ctx, keyPrefix)
+			switch {
+			case err != nil:
+				if !send(ctx, errorChannel, err) {
+					return
+				}
+			case previous == nil:
+				// like the message bus watch, send a nil value once the first keys have been read
+				// for go-mod-bootstrap to ignore the first change event
+				previous = current
+				if !send(ctx, updateChannel, nil) {
+					return
+				}
+			case !maps.Equal(previous, current):
+				previous = current
+				if err := kvtree.Decode(decodePrefix, current, configuration); err != nil {
+					if !send(ctx, errorChannel, err) {
+						return
 					}
-					// if the updated key from the message payload hasn't been found in Keeper
-					// skip this subscribed message payload
-					if !foundUpdatedKey {
-						continue
+				} else if !send(ctx, updateChannel, configuration) {
+					return
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// WatchForEvents subscribes to the changes of the keys under waitKey published by Core Keeper and sends a ChangeEvent
+// for every key which has been added, modified or deleted. Core Keeper has no index, so the Index of the events is
+// the sequence number of the change observed by this watch. Without a MessageClient, the message bus set in the
+// optional properties is used.
+func (client *keeperClient) WatchForEvents(ctx context.Context, eventChannel chan<- types.ChangeEvent, errorChannel chan<- error, waitKey string, messageBus messaging.MessageClient) {
+	// owned is set if the message bus has been created for this watch, rather than given by the caller
+	owned := false
+	if messageBus == nil {
+		var err error
+		messageBus, err = client.newMessageBus(ctx)
+		if err != nil {
+			client.reportWatchError(ctx, errorChannel, err)
+			return
+		}
+		owned = true
+	}
+
+	messages := make(chan msgTypes.MessageEnvelope)
+	topic := path.Join(keeperTopicPrefix, client.configBasePath, waitKey, "#")
+	topics := []msgTypes.TopicChannel{
+		{
+			Topic:    topic,
+			Messages: messages,
+		},
+	}
+
+	watchErrors := make(chan error)
+	err := messageBus.Subscribe(topics, watchErrors)
+	if err != nil {
+		release(messageBus, owned)
+		client.reportWatchError(ctx, errorChannel, err)
+		return
+	}
+
+	// The keys are read once subscribed, so that no change is missed between the two
+	keyPrefix := path.Join(client.configBasePath, waitKey)
+	snapshot, err := client.readSubtree(ctx, keyPrefix)
+	if err != nil {
+		release(messageBus, owned)
+		client.reportWatchError(ctx, errorChannel, err)
+		return
+	}
+
+	ctx, watchDone := client.startWatch(ctx)
+
+	go func() {
+		defer watchDone()
+		defer release(messageBus, owned)
+
+		var index uint64
+		// sendEvents sends the changes from the snapshot to current, unless ctx is done first
+		sendEvents := func(current map[string]string) bool {
+			events := kvtree.Diff(snapshot, current)
+			if len(events) > 0 {
+				index++
+			}
+			for _, event := range events {
+				event.Index = index
+				if !send(ctx, eventChannel, event) {
+					return false
+				}
+			}
+			snapshot = current
+			return true
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e := <-watchErrors:
+				if !send(ctx, errorChannel, error(&types.DegradedError{Key: waitKey, Err: e})) {
+					return
+				}
+				if !client.resubscribe(ctx, messageBus, owned, topics, watchErrors) {
+					return
+				}
+
+				// the changes published while not subscribed are lost, so compare the whole subtree once subscribed again
+				current, err := client.readSubtree(ctx, keyPrefix)
+				if err != nil {
+					if !send(ctx, errorChannel, err) {
+						return
 					}
+					continue
+				}
+				if !sendEvents(current) {
+					return
+				}
+			case msgEnvelope := <-messages:
+				if msgEnvelope.ContentType != http.ContentTypeJSON {
+					continue
 				}
 
-				// decode KV DTO array to configuration struct
-				err = decode(keyPrefix, kvConfigs.KVs, configuration)
+				// The message only holds the key which has been published, so compare the whole subtree
+				// to also catch the keys deleted along with it
+				current, err := client.readSubtree(ctx, keyPrefix)
 				if err != nil {
+					if !send(ctx, errorChannel, err) {
+						return
+					}
 					continue
 				}
-				updateChannel <- configuration
+
+				if !sendEvents(current) {
+					return
+				}
 			}
 		}
 	}()
 }
 
+// readSubtree gets the values of the keys under keyPrefix, keyed by their path relative to the base path
+func (client *keeperClient) readSubtree(ctx context.Context, keyPrefix string) (map[string]string, error) {
+	var resp dtos.MultiKVResponse
+	err := client.execute(ctx, client.timeouts.GetRead(), func(ctx context.Context) (err error) {
+		resp, err = client.keeperClient.KV().Get(ctx, keyPrefix)
+		return err
+	})
+	if err != nil {
+		var errResp http.ErrorResponse
+		if !errors.As(err, &errResp) || errResp.StatusCode != nethttp.StatusNotFound {
+			return nil, fmt.Errorf("unable to get values for %s from Core Keeper: %w", keyPrefix, err)
+		}
+	}
+
+	return client.toSubtree(keyPrefix, resp.KVs), nil
+}
+
+// toSubtree converts the KV DTOs under keyPrefix to values keyed by their path relative to the base path
+func (client *keeperClient) toSubtree(keyPrefix string, kvs []dtos.KV) map[string]string {
+	pairs := make(map[string]string, len(kvs))
+	for _, kv := range kvs {
+		if kvtree.HasPrefix(kv.Key, keyPrefix) {
+			pairs[strings.TrimPrefix(kv.Key, client.configBasePath+api.KeyDelimiter)] = cast.ToString(kv.Value)
+		}
+	}
+
+	return pairs
+}
+
+// applyChange applies the change of a single key published by Core Keeper to the local copy of the subtree under
+// keyPrefix and reports whether its value has changed. The change isn't applied if the subtree is unknown, if the
+// change isn't a single value under keyPrefix or if it conflicts with the keys of the subtree, i.e. a value would
+// also be a directory, in which case the subtree must be read again.
+func (client *keeperClient) applyChange(subtree map[string]string, keyPrefix string, change dtos.KV) (changed bool, applied bool) {
+	if subtree == nil || change.Key == keyPrefix || !kvtree.HasPrefix(change.Key, keyPrefix) {
+		return false, false
+	}
+
+	switch change.Value.(type) {
+	case map[string]any, []any:
+		return false, false
+	}
+
+	key := strings.TrimPrefix(change.Key, client.configBasePath+api.KeyDelimiter)
+	for existing := range subtree {
+		if existing != key && (kvtree.HasPrefix(existing, key) || kvtree.HasPrefix(key, existing)) {
+			return false, false
+		}
+	}
+
+	value := cast.ToString(change.Value)
+	if current, ok := subtree[key]; ok && current == value {
+		return false, true
+	}
+
+	subtree[key] = value
+	return true, true
+}
+
+// resubscribe renews the subscription of a watch after an error of its message bus, retrying with backoff until it
+// succeeds or ctx is done, in which case false is returned. The subscription, which is usually still alive as most
+// errors only concern a single message, is dropped first so that the changes aren't received twice. The message bus is
+// only connected again if subscribing fails. A message bus given by the caller is left untouched, as it may be shared
+// with other watches subscribed to the same topic, and keeps its subscriptions across reconnections itself.
+// The errors reported meanwhile by the message bus are dropped, as the watch is already known to be degraded.
+func (client *keeperClient) resubscribe(ctx context.Context, messageBus messaging.MessageClient, owned bool, topics []msgTypes.TopicChannel, watchErrors chan error) bool {
+	if !owned {
+		return true
+	}
+
+	for attempt := 1; ; attempt++ {
+		for _, topic := range topics {
+			_ = messageBus.Unsubscribe(topic.Topic)
+		}
+
+		err := messageBus.Subscribe(topics, watchErrors)
+		if err != nil && messageBus.Connect() == nil {
+			err = messageBus.Subscribe(topics, watchErrors)
+		}
+		if err == nil {
+			return true
+		}
+
+		timer := time.NewTimer(retry.Backoff(client.retryPolicy, attempt))
+		for waiting := true; waiting; {
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return false
+			case <-watchErrors:
+			case <-timer.C:
+				waiting = false
+			}
+		}
+	}
+}
+
+// release disconnects the message bus of a watch once it has exited, unless it has been given by the caller, who
+// keeps it connected as it may be shared with other watches
+func release(messageBus messaging.MessageClient, owned bool) {
+	if owned {
+		_ = messageBus.Disconnect()
+	}
+}
+
+// StopWatching causes all WatchForChanges and WatchForEvents processing to stop and waits until they have exited.
+// Watches set up afterwards are not affected.
 func (client *keeperClient) StopWatching() {
-	client.watchingDone <- true
+	client.watchMutex.Lock()
+	defer client.watchMutex.Unlock()
+
+	client.watchingDone()
+	client.watchingWait.Wait()
+
+	client.watchingDoneCtx, client.watchingDone = context.WithCancel(context.Background())
+}
+
+// Close stops all watches along with the background renewal of the Access Token. The client must not be used
+// afterward.
+func (client *keeperClient) Close() error {
+	client.StopWatching()
+	client.tokenProvider.Stop()
+	return nil
 }
 
+// startWatch tracks a new watch, which is to stop once either ctx is done or StopWatching is called.
+// The returned function must be called once the watch has exited.
+func (client *keeperClient) startWatch(ctx context.Context) (context.Context, func()) {
+	client.watchMutex.Lock()
+	defer client.watchMutex.Unlock()
+
+	client.watchingWait.Add(1)
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(client.watchingDoneCtx, cancel)
+
+	return ctx, func() {
+		stop()
+		cancel()
+		client.watchingWait.Done()
+	}
+}
+
+// send sends the value on the channel unless ctx is done first, in which case false is returned
+func send[V any](ctx context.Context, channel chan<- V, value V) bool {
+	select {
+	case channel <- value:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// ConfigurationValueExists checks if a configuration value exists in Core Keeper
 func (client *keeperClient) ConfigurationValueExists(name string) (bool, error) {
+	return client.ConfigurationValueExistsCtx(context.Background(), name)
+}
+
+// ConfigurationValueExistsCtx checks if a configuration value exists in Core Keeper
+func (client *keeperClient) ConfigurationValueExistsCtx(ctx context.Context, name string) (bool, error) {
 	keyPath := client.fullPath(name)
-	res, err := client.keeperClient.KV().Keys(keyPath)
+	var res dtos.MultiKeyResponse
+	err := client.execute(ctx, client.timeouts.GetRead(), func(ctx context.Context) (err error) {
+		res, err = client.keeperClient.KV().Keys(ctx, keyPath)
+		return err
+	})
 	if err != nil {
-		return false, fmt.Errorf("checking configuration existence from Core Keeper failed: %v", err)
+		return false, fmt.Errorf("checking configuration existence from Core Keeper failed: %w", err)
 	}
 	if len(res.Keys) == 0 {
 		return false, nil
@@ -260,14 +906,29 @@ func (client *keeperClient) ConfigurationValueExists(name string) (bool, error)
 	return true, nil
 }
 
+// GetConfigurationValue gets a specific configuration value from Core Keeper
 func (client *keeperClient) GetConfigurationValue(name string) ([]byte, error) {
+	return client.GetConfigurationValueCtx(context.Background(), name)
+}
+
+// GetConfigurationValueCtx gets a specific configuration value from Core Keeper
+func (client *keeperClient) GetConfigurationValueCtx(ctx context.Context, name string) ([]byte, error) {
 	keyPath := client.fullPath(name)
-	return client.GetConfigurationValueByFullPath(keyPath)
+	return client.GetConfigurationValueByFullPathCtx(ctx, keyPath)
 }
 
 // GetConfigurationValueByFullPath gets a specific configuration value given the full path from Core Keeper
 func (client *keeperClient) GetConfigurationValueByFullPath(name string) ([]byte, error) {
-	resp, err := client.keeperClient.KV().Get(name)
+	return client.GetConfigurationValueByFullPathCtx(context.Background(), name)
+}
+
+// GetConfigurationValueByFullPathCtx gets a specific configuration value given the full path from Core Keeper
+func (client *keeperClient) GetConfigurationValueByFullPathCtx(ctx context.Context, name string) ([]byte, error) {
+	var resp dtos.MultiKVResponse
+	err := client.execute(ctx, client.timeouts.GetRead(), func(ctx context.Context) (err error) {
+		resp, err = client.keeperClient.KV().Get(ctx, name)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -280,20 +941,110 @@ func (client *keeperClient) GetConfigurationValueByFullPath(name string) ([]byte
 	return []byte(valueStr), nil
 }
 
+// PutConfigurationValue puts a specific configuration value into Core Keeper
 func (client *keeperClient) PutConfigurationValue(name string, value []byte) error {
+	return client.PutConfigurationValueCtx(context.Background(), name, value)
+}
+
+// PutConfigurationValueCtx puts a specific configuration value into Core Keeper
+func (client *keeperClient) PutConfigurationValueCtx(ctx context.Context, name string, value []byte) error {
 	keyPath := client.fullPath(name)
-	err := client.keeperClient.KV().Put(keyPath, value)
+	err := client.execute(ctx, client.timeouts.GetWrite(), func(ctx context.Context) error {
+		return client.keeperClient.KV().Put(ctx, keyPath, value)
+	})
 	if err != nil {
-		return fmt.Errorf("unable to JSON marshal configStruct, err: %v", err)
+		return fmt.Errorf("unable to JSON marshal configStruct, err: %w", err)
 	}
 	return nil
 }
 
+// GetConfigurationValueWithVersion gets a specific configuration value from Core Keeper along with its version.
+// Core Keeper doesn't keep revisions of the values, so the version is derived from the value itself.
+func (client *keeperClient) GetConfigurationValueWithVersion(name string) ([]byte, uint64, error) {
+	return client.GetConfigurationValueWithVersionCtx(context.Background(), name)
+}
+
+// GetConfigurationValueWithVersionCtx gets a specific configuration value from Core Keeper along with its version.
+// Core Keeper doesn't keep revisions of the values, so the version is derived from the value itself.
+func (client *keeperClient) GetConfigurationValueWithVersionCtx(ctx context.Context, name string) ([]byte, uint64, error) {
+	keyPath := client.fullPath(name)
+	var resp dtos.MultiKVResponse
+	err := client.execute(ctx, client.timeouts.GetRead(), func(ctx context.Context) (err error) {
+		resp, err = client.keeperClient.KV().Get(ctx, keyPath)
+		return err
+	})
+	if err != nil {
+		var errResp http.ErrorResponse
+		if errors.As(err, &errResp) && errResp.StatusCode == nethttp.StatusNotFound {
+			return nil, 0, nil
+		}
+		return nil, 0, fmt.Errorf("unable to get value for %s from Core Keeper: %w", keyPath, err)
+	}
+
+	// The keys under keyPath are returned as well, so look for the exact key
+	for _, kv := range resp.KVs {
+		if kv.Key == keyPath {
+			value := []byte(cast.ToString(kv.Value))
+			return value, valueVersion(value), nil
+		}
+	}
+
+	return nil, 0, nil
+}
+
+// PutConfigurationValueCAS puts a specific configuration value into Core Keeper only if its version still is expectedVersion
+func (client *keeperClient) PutConfigurationValueCAS(name string, value []byte, expectedVersion uint64) error {
+	return client.PutConfigurationValueCASCtx(context.Background(), name, value, expectedVersion)
+}
+
+// PutConfigurationValueCASCtx puts a specific configuration value into Core Keeper only if its version still is expectedVersion.
+// Core Keeper has no native compare-and-swap, so it is emulated by comparing the current version right before the write.
+// Writes made through this client are serialized, but a write from another client between the read and the write
+// can't be detected.
+func (client *keeperClient) PutConfigurationValueCASCtx(ctx context.Context, name string, value []byte, expectedVersion uint64) error {
+	client.casMutex.Lock()
+	defer client.casMutex.Unlock()
+
+	_, actualVersion, err := client.GetConfigurationValueWithVersionCtx(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if actualVersion != expectedVersion {
+		return &types.ConflictError{Key: client.fullPath(name), ExpectedVersion: expectedVersion, ActualVersion: actualVersion}
+	}
+
+	return client.PutConfigurationValueCtx(ctx, name, value)
+}
+
+// valueVersion derives the version of a value from its FNV-1a hash. 0 is reserved for values which don't exist.
+func valueVersion(value []byte) uint64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write(value)
+
+	version := hash.Sum64()
+	if version == 0 {
+		version = 1
+	}
+
+	return version
+}
+
+// GetConfigurationKeys returns all keys under name
 func (client *keeperClient) GetConfigurationKeys(name string) ([]string, error) {
+	return client.GetConfigurationKeysCtx(context.Background(), name)
+}
+
+// GetConfigurationKeysCtx returns all keys under name
+func (client *keeperClient) GetConfigurationKeysCtx(ctx context.Context, name string) ([]string, error) {
 	keyPath := client.fullPath(name)
-	resp, err := client.keeperClient.KV().Keys(keyPath)
+	var resp dtos.MultiKeyResponse
+	err := client.execute(ctx, client.timeouts.GetRead(), func(ctx context.Context) (err error) {
+		resp, err = client.keeperClient.KV().Keys(ctx, keyPath)
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("unable to get list of keys for %s from Core Keeper: %v", keyPath, err)
+		return nil, fmt.Errorf("unable to get list of keys for %s from Core Keeper: %w", keyPath, err)
 	}
 
 	var list []string
@@ -302,3 +1053,37 @@ func (client *keeperClient) GetConfigurationKeys(name string) ([]string, error)
 	}
 	return list, nil
 }
+
+// DeleteConfigurationValue removes a specific configuration value from Core Keeper
+func (client *keeperClient) DeleteConfigurationValue(name string) error {
+	return client.DeleteConfigurationValueCtx(context.Background(), name)
+}
+
+// DeleteConfigurationValueCtx removes a specific configuration value from Core Keeper
+func (client *keeperClient) DeleteConfigurationValueCtx(ctx context.Context, name string) error {
+	keyPath := client.fullPath(name)
+	err := client.execute(ctx, client.timeouts.GetWrite(), func(ctx context.Context) error {
+		return client.keeperClient.KV().Delete(ctx, keyPath)
+	})
+	if err != nil {
+		return fmt.Errorf("unable to delete value for %s from Core Keeper: %w", keyPath, err)
+	}
+	return nil
+}
+
+// DeleteSubConfiguration removes all configuration values located under name from Core Keeper
+func (client *keeperClient) DeleteSubConfiguration(name string) error {
+	return client.DeleteSubConfigurationCtx(context.Background(), name)
+}
+
+// DeleteSubConfigurationCtx removes all configuration values located under name from Core Keeper
+func (client *keeperClient) DeleteSubConfigurationCtx(ctx context.Context, name string) error {
+	keyPath := client.fullPath(name)
+	err := client.execute(ctx, client.timeouts.GetWrite(), func(ctx context.Context) error {
+		return client.keeperClient.KV().DeleteKeys(ctx, keyPath)
+	})
+	if err != nil {
+		return fmt.Errorf("unable to delete sub configuration %s from Core Keeper: %w", keyPath, err)
+	}
+	return nil
+}
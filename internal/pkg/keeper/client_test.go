@@ -6,16 +6,33 @@
 package keeper
 
 import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"slices"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/keeper/dtos"
+	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/keeper/models"
+	httpUtils "github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/keeper/utils/http"
 	"github.com/edgexfoundry/go-mod-configuration/v3/pkg/types"
 
+	"github.com/edgexfoundry/go-mod-messaging/v3/messaging"
+	messagingMocks "github.com/edgexfoundry/go-mod-messaging/v3/messaging/mocks"
+	msgTypes "github.com/edgexfoundry/go-mod-messaging/v3/pkg/types"
+
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -50,7 +67,7 @@ func makeCoreKeeperClient(serviceName string) *keeperClient {
 		BasePath: serviceName,
 	}
 
-	client := NewKeeperClient(config)
+	client, _ := NewKeeperClient(config)
 	return client
 }
 
@@ -72,7 +89,7 @@ func reset(t *testing.T, client *keeperClient) {
 	} else {
 		// delete the key(s) created in each test if testing on real Keeper service
 		key := client.configBasePath
-		err := client.keeperClient.KV().DeleteKeys(key)
+		err := client.keeperClient.KV().DeleteKeys(context.Background(), key)
 		if !assert.NoError(t, err) {
 			t.Fatal()
 		}
@@ -248,6 +265,46 @@ func TestPutConfigurationMapWithOverwrite(t *testing.T) {
 	}
 }
 
+func TestPutConfigurationMapBatch(t *testing.T) {
+	client := makeCoreKeeperClient(getUniqueServiceName())
+
+	// delete the configuration created
+	defer reset(t, client)
+
+	require.NoError(t, client.PutConfigurationValue("nestedNode/field1", []byte("existing")))
+
+	configMap := createConfigMap()
+	result, err := client.PutConfigurationMapBatch(context.Background(), configMap, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nestedNode/field1"}, result.Skipped)
+	assert.Contains(t, result.Written, "nestedNode/field2")
+	assert.NotContains(t, result.Written, "nestedNode/field1")
+	assert.Empty(t, result.Failed)
+
+	value, err := client.GetConfigurationValue("nestedNode/field1")
+	require.NoError(t, err)
+	assert.Equal(t, "existing", string(value))
+
+	result, err = client.PutConfigurationMapBatch(context.Background(), configMap, true)
+	require.NoError(t, err)
+	assert.Contains(t, result.Written, "nestedNode/field1")
+	assert.Empty(t, result.Skipped)
+
+	value, err = client.GetConfigurationValue("nestedNode/field1")
+	require.NoError(t, err)
+	assert.Equal(t, "value1", string(value))
+
+	t.Run("Service unavailable", func(t *testing.T) {
+		unavailable, err := NewKeeperClient(types.ServiceConfig{Host: "localhost", Port: 1, BasePath: getUniqueServiceName()})
+		require.NoError(t, err)
+
+		result, err := unavailable.PutConfigurationMapBatch(context.Background(), configMap, true)
+		require.Error(t, err)
+		assert.ElementsMatch(t, pairKeys(convertInterfaceToPairs("", configMap)), result.Failed)
+		assert.Empty(t, result.Written)
+	})
+}
+
 func TestPutConfiguration(t *testing.T) {
 	client := makeCoreKeeperClient(getUniqueServiceName())
 
@@ -385,7 +442,7 @@ func TestPutConfigurationValue(t *testing.T) {
 	err := client.PutConfigurationValue(key, expected)
 	assert.NoError(t, err)
 
-	resp, err := client.keeperClient.KV().Get(client.fullPath(key))
+	resp, err := client.keeperClient.KV().Get(context.Background(), client.fullPath(key))
 	if !assert.NoError(t, err) {
 		t.Fatal()
 	}
@@ -397,3 +454,1188 @@ func TestPutConfigurationValue(t *testing.T) {
 
 	assert.Equal(t, expected, actual)
 }
+
+func TestPutConfigurationValueCAS(t *testing.T) {
+	client := makeCoreKeeperClient(getUniqueServiceName())
+
+	// delete the configuration created
+	defer reset(t, client)
+
+	value, version, err := client.GetConfigurationValueWithVersion("Writable/LogLevel")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+	assert.Zero(t, version)
+
+	// Version 0 only creates the value if it doesn't exist
+	require.NoError(t, client.PutConfigurationValueCAS("Writable/LogLevel", []byte("INFO"), 0))
+
+	value, version, err = client.GetConfigurationValueWithVersion("Writable/LogLevel")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("INFO"), value)
+	assert.NotZero(t, version)
+
+	// Another operator changes the value in the meantime
+	require.NoError(t, client.PutConfigurationValue("Writable/LogLevel", []byte("DEBUG")))
+
+	err = client.PutConfigurationValueCAS("Writable/LogLevel", []byte("WARN"), version)
+	var conflictErr *types.ConflictError
+	require.ErrorAs(t, err, &conflictErr)
+	assert.Equal(t, version, conflictErr.ExpectedVersion)
+	assert.NotEqual(t, version, conflictErr.ActualVersion)
+
+	_, version, err = client.GetConfigurationValueWithVersion("Writable/LogLevel")
+	require.NoError(t, err)
+	require.NoError(t, client.PutConfigurationValueCAS("Writable/LogLevel", []byte("WARN"), version))
+
+	value, err = client.GetConfigurationValue("Writable/LogLevel")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("WARN"), value)
+
+	err = client.PutConfigurationValueCAS("Writable/LogLevel", []byte("ERROR"), 0)
+	require.ErrorAs(t, err, &conflictErr)
+}
+
+func TestDeleteConfigurationValue(t *testing.T) {
+	client := makeCoreKeeperClient(getUniqueServiceName())
+
+	// delete the configuration created
+	defer reset(t, client)
+
+	require.NoError(t, client.PutConfigurationValue("Foo", []byte("bar")))
+	require.NoError(t, client.PutConfigurationValue("Foo/Bar", []byte("bar")))
+
+	err := client.DeleteConfigurationValue("Foo")
+	require.NoError(t, err)
+
+	keys, err := client.GetConfigurationKeys("Foo")
+	require.NoError(t, err)
+	assert.Equal(t, []string{client.fullPath("Foo/Bar")}, keys)
+
+	// Deleting a value which doesn't exist is not an error
+	err = client.DeleteConfigurationValue("Foo")
+	require.NoError(t, err)
+}
+
+func TestDeleteSubConfiguration(t *testing.T) {
+	client := makeCoreKeeperClient(getUniqueServiceName())
+
+	// delete the configuration created
+	defer reset(t, client)
+
+	require.NoError(t, client.PutConfigurationValue("Writable/LogLevel", []byte("INFO")))
+	require.NoError(t, client.PutConfigurationValue("Writable/InsecureSecrets/DB/Path", []byte("redisdb")))
+	require.NoError(t, client.PutConfigurationValue("WritableExtra", []byte("true")))
+
+	err := client.DeleteSubConfiguration("Writable")
+	require.NoError(t, err)
+
+	keys, err := client.GetConfigurationKeys("Writable")
+	require.NoError(t, err)
+	assert.Equal(t, []string{client.fullPath("WritableExtra")}, keys)
+
+	// Deleting a sub configuration which doesn't exist is not an error
+	err = client.DeleteSubConfiguration("Writable")
+	require.NoError(t, err)
+}
+
+func TestContextCanceled(t *testing.T) {
+	client := makeCoreKeeperClient(getUniqueServiceName())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.GetConfigurationValueCtx(ctx, "Foo")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	err = client.PutConfigurationValueCtx(ctx, "Foo", []byte("bar"))
+	require.Error(t, err)
+
+	_, err = client.GetConfigurationCtx(ctx, &TestConfig{})
+	require.Error(t, err)
+
+	assert.False(t, client.IsAliveCtx(ctx))
+}
+
+func TestTimeouts(t *testing.T) {
+	slowServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		time.Sleep(500 * time.Millisecond)
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer slowServer.Close()
+
+	URL, _ := url.Parse(slowServer.URL)
+	slowPort, _ := strconv.Atoi(URL.Port())
+	client, err := NewKeeperClient(types.ServiceConfig{
+		Host:     URL.Hostname(),
+		Port:     slowPort,
+		BasePath: getUniqueServiceName(),
+		Timeouts: types.TimeoutInfo{
+			Load:     50 * time.Millisecond,
+			Read:     50 * time.Millisecond,
+			Write:    50 * time.Millisecond,
+			Liveness: 50 * time.Millisecond,
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = client.GetConfigurationValue("Foo")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	err = client.PutConfigurationValue("Foo", []byte("bar"))
+	assert.Error(t, err)
+
+	_, err = client.GetConfiguration(&TestConfig{})
+	assert.Error(t, err)
+
+	assert.False(t, client.IsAlive())
+}
+
+func TestRetry(t *testing.T) {
+	failures := 2
+	requests := 0
+	flakyServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requests++
+		writer.Header().Set("Content-Type", "application/json")
+		if requests <= failures {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+			_ = json.NewEncoder(writer).Encode(httpUtils.ErrorResponse{Message: "unavailable", StatusCode: http.StatusServiceUnavailable})
+			return
+		}
+		_ = json.NewEncoder(writer).Encode(dtos.MultiKeyResponse{Keys: []dtos.KeyOnly{"key"}})
+	}))
+	defer flakyServer.Close()
+
+	URL, _ := url.Parse(flakyServer.URL)
+	flakyPort, _ := strconv.Atoi(URL.Port())
+	config := types.ServiceConfig{
+		Host:     URL.Hostname(),
+		Port:     flakyPort,
+		BasePath: getUniqueServiceName(),
+		Retry: types.RetryInfo{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+		},
+	}
+
+	t.Run("Success after retries", func(t *testing.T) {
+		requests = 0
+		client, err := NewKeeperClient(config)
+		require.NoError(t, err)
+
+		exists, err := client.HasConfiguration()
+		require.NoError(t, err)
+		assert.True(t, exists)
+		assert.Equal(t, 3, requests)
+	})
+
+	t.Run("Attempts exhausted", func(t *testing.T) {
+		requests = 0
+		failures = 5
+		client, err := NewKeeperClient(config)
+		require.NoError(t, err)
+
+		_, err = client.HasConfiguration()
+		require.Error(t, err)
+		assert.Equal(t, 3, requests)
+
+		var retryErr *types.RetryError
+		require.ErrorAs(t, err, &retryErr)
+		assert.Len(t, retryErr.Attempts, 3)
+
+		var errResp httpUtils.ErrorResponse
+		require.ErrorAs(t, err, &errResp)
+		assert.Equal(t, http.StatusServiceUnavailable, errResp.StatusCode)
+	})
+}
+
+func TestWatchForEvents(t *testing.T) {
+	client := makeCoreKeeperClient(getUniqueServiceName())
+
+	// delete the configuration created
+	defer reset(t, client)
+
+	require.NoError(t, client.PutConfigurationValue("Writable/LogLevel", []byte("INFO")))
+	require.NoError(t, client.PutConfigurationValue("Writable/Timeout", []byte("5s")))
+
+	var messages chan<- msgTypes.MessageEnvelope
+	messageBus := &messagingMocks.MessageClient{}
+	messageBus.On("Subscribe", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		messages = args.Get(0).([]msgTypes.TopicChannel)[0].Messages
+	}).Return(nil)
+	messageBus.On("Disconnect").Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	eventChannel := make(chan types.ChangeEvent)
+	errorChannel := make(chan error, 1)
+
+	client.WatchForEvents(ctx, eventChannel, errorChannel, "Writable", messageBus)
+	require.NotNil(t, messages)
+
+	publish := func(key string, value string) {
+		payload, _ := json.Marshal(dtos.KV{Key: client.fullPath(key), Value: value})
+		messages <- msgTypes.MessageEnvelope{ContentType: httpUtils.ContentTypeJSON, Payload: payload}
+	}
+
+	require.NoError(t, client.PutConfigurationValue("Writable/LogLevel", []byte("DEBUG")))
+	require.NoError(t, client.DeleteConfigurationValue("Writable/Timeout"))
+	publish("Writable/LogLevel", "DEBUG")
+
+	expected := []types.ChangeEvent{
+		{Key: "Writable/LogLevel", OldValue: []byte("INFO"), NewValue: []byte("DEBUG"), Type: types.ChangeModified, Index: 1},
+		{Key: "Writable/Timeout", OldValue: []byte("5s"), Type: types.ChangeDeleted, Index: 1},
+	}
+	for _, expectedEvent := range expected {
+		select {
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting on %s event for %s", expectedEvent.Type, expectedEvent.Key)
+		case event := <-eventChannel:
+			assert.Equal(t, expectedEvent, event)
+		case err := <-errorChannel:
+			t.Fatalf("received WatchForEvents error: %v", err)
+		}
+	}
+
+	require.NoError(t, client.PutConfigurationValue("Writable/InsecureSecrets/DB/Path", []byte("redisdb")))
+	publish("Writable/InsecureSecrets/DB/Path", "redisdb")
+
+	select {
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting on added event")
+	case event := <-eventChannel:
+		assert.Equal(t, types.ChangeEvent{Key: "Writable/InsecureSecrets/DB/Path", NewValue: []byte("redisdb"), Type: types.ChangeAdded, Index: 2}, event)
+	case err := <-errorChannel:
+		t.Fatalf("received WatchForEvents error: %v", err)
+	}
+
+	t.Run("Set up failure", func(t *testing.T) {
+		failingBus := &messagingMocks.MessageClient{}
+		failingBus.On("Subscribe", mock.Anything, mock.Anything).Return(errors.New("subscribe failed"))
+
+		// the error is sent once the caller reads from the channels
+		eventChannel := make(chan types.ChangeEvent)
+		errorChannel := make(chan error)
+		client.WatchForEvents(ctx, eventChannel, errorChannel, "Writable", failingBus)
+
+		select {
+		case err := <-errorChannel:
+			assert.ErrorContains(t, err, "subscribe failed")
+		case event := <-eventChannel:
+			t.Fatalf("unexpected event: %v", event)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting on subscribe error")
+		}
+	})
+}
+
+func TestStopWatching(t *testing.T) {
+	client := makeCoreKeeperClient(getUniqueServiceName())
+
+	// delete the configuration created
+	defer reset(t, client)
+
+	require.NoError(t, client.PutConfigurationValue("Writable/LogLevel", []byte("INFO")))
+
+	newMessageBus := func() *messagingMocks.MessageClient {
+		messageBus := &messagingMocks.MessageClient{}
+		messageBus.On("Subscribe", mock.Anything, mock.Anything).Return(nil)
+		messageBus.On("Disconnect").Return(nil)
+		return messageBus
+	}
+
+	// The update channels aren't read, so the watches must stop while blocked on sending the first update
+	updateChannel := make(chan interface{})
+	errorChannel := make(chan error)
+	eventChannel := make(chan types.ChangeEvent)
+
+	firstBus := newMessageBus()
+	secondBus := newMessageBus()
+	eventsBus := newMessageBus()
+	client.WatchForChanges(updateChannel, errorChannel, &LoggingInfo{}, "Writable", firstBus)
+	client.WatchForChanges(updateChannel, errorChannel, &LoggingInfo{}, "Writable", secondBus)
+	client.WatchForEvents(context.Background(), eventChannel, errorChannel, "Writable", eventsBus)
+
+	// StopWatching only returns once all the watches have exited
+	client.StopWatching()
+
+	// the message buses given by the caller are left connected
+	firstBus.AssertNotCalled(t, "Disconnect")
+	secondBus.AssertNotCalled(t, "Disconnect")
+	eventsBus.AssertNotCalled(t, "Disconnect")
+
+	// Watches set up after StopWatching keep on running
+	client.WatchForChanges(updateChannel, errorChannel, &LoggingInfo{}, "Writable", newMessageBus())
+
+	select {
+	case update := <-updateChannel:
+		assert.Nil(t, update)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting on first update")
+	}
+
+	client.StopWatching()
+}
+
+func TestWatchSharedMessageBus(t *testing.T) {
+	client := makeCoreKeeperClient(getUniqueServiceName())
+
+	// delete the configuration created
+	defer reset(t, client)
+	defer client.StopWatching()
+
+	require.NoError(t, client.PutConfigurationValue("Writable/LogLevel", []byte("INFO")))
+
+	var lock sync.Mutex
+	var topics []msgTypes.TopicChannel
+	messageBus := &messagingMocks.MessageClient{}
+	messageBus.On("Subscribe", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		lock.Lock()
+		defer lock.Unlock()
+		topics = append(topics, args.Get(0).([]msgTypes.TopicChannel)...)
+	}).Return(nil)
+
+	receive := func(updates chan interface{}) interface{} {
+		select {
+		case update := <-updates:
+			return update
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting on configuration update")
+		}
+		return nil
+	}
+
+	type WritableInfo struct {
+		LogLevel string
+	}
+
+	firstUpdates := make(chan interface{})
+	secondUpdates := make(chan interface{})
+	errorChannel := make(chan error, 1)
+
+	firstWatcher := client.Watch(context.Background(), firstUpdates, errorChannel, &WritableInfo{}, "Writable", messageBus)
+	secondWatcher := client.Watch(context.Background(), secondUpdates, errorChannel, &WritableInfo{}, "Writable", messageBus)
+	assert.Nil(t, receive(firstUpdates))
+	assert.Nil(t, receive(secondUpdates))
+
+	firstWatcher.Stop()
+	assert.ErrorIs(t, firstWatcher.Err(), context.Canceled)
+	// the shared message bus is neither disconnected nor connected again by the watch
+	messageBus.AssertNotCalled(t, "Disconnect")
+	messageBus.AssertNotCalled(t, "Connect")
+
+	// the other watch still receives the changes published on the shared message bus
+	require.NoError(t, client.PutConfigurationValue("Writable/LogLevel", []byte("DEBUG")))
+	payload, err := json.Marshal(dtos.KV{Key: client.fullPath("Writable/LogLevel"), Value: "DEBUG"})
+	require.NoError(t, err)
+
+	lock.Lock()
+	secondTopic := topics[1]
+	lock.Unlock()
+	secondTopic.Messages <- msgTypes.MessageEnvelope{ContentType: httpUtils.ContentTypeJSON, Payload: payload}
+
+	update := receive(secondUpdates)
+	require.IsType(t, &WritableInfo{}, update)
+	assert.Equal(t, "DEBUG", update.(*WritableInfo).LogLevel)
+	assert.NoError(t, secondWatcher.Err())
+}
+
+func TestWatch(t *testing.T) {
+	client := makeCoreKeeperClient(getUniqueServiceName())
+
+	// delete the configuration created
+	defer reset(t, client)
+
+	newMessageBus := func() *messagingMocks.MessageClient {
+		messageBus := &messagingMocks.MessageClient{}
+		messageBus.On("Subscribe", mock.Anything, mock.Anything).Return(nil)
+		messageBus.On("Disconnect").Return(nil)
+		return messageBus
+	}
+
+	writableUpdates := make(chan interface{}, 1)
+	secretsUpdates := make(chan interface{}, 1)
+	errorChannel := make(chan error, 1)
+
+	writableWatcher := client.Watch(context.Background(), writableUpdates, errorChannel, &LoggingInfo{}, "Writable", newMessageBus())
+	secretsWatcher := client.Watch(context.Background(), secretsUpdates, errorChannel, &LoggingInfo{}, "InsecureSecrets", newMessageBus())
+
+	writableWatcher.Stop()
+	assert.ErrorIs(t, writableWatcher.Err(), context.Canceled)
+
+	select {
+	case <-secretsWatcher.Done():
+		t.Fatal("stopping a watch must not stop the other watches")
+	default:
+	}
+	assert.NoError(t, secretsWatcher.Err())
+
+	client.StopWatching()
+	<-secretsWatcher.Done()
+	assert.ErrorIs(t, secretsWatcher.Err(), context.Canceled)
+
+	t.Run("Set up failure", func(t *testing.T) {
+		messageBus := &messagingMocks.MessageClient{}
+		messageBus.On("Subscribe", mock.Anything, mock.Anything).Return(errors.New("subscribe failed"))
+		messageBus.On("Disconnect").Return(nil)
+
+		watcher := client.Watch(context.Background(), writableUpdates, errorChannel, &LoggingInfo{}, "Writable", messageBus)
+
+		select {
+		case <-watcher.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting on failed watch to be done")
+		}
+		require.Error(t, watcher.Err())
+		assert.NotErrorIs(t, watcher.Err(), context.Canceled)
+	})
+}
+
+func TestWatchForChangesPolling(t *testing.T) {
+	config := types.ServiceConfig{
+		Host:         testHost,
+		Port:         port,
+		BasePath:     getUniqueServiceName(),
+		PollInterval: 10 * time.Millisecond,
+	}
+	client, err := NewKeeperClient(config)
+	require.NoError(t, err)
+
+	// delete the configuration created
+	defer reset(t, client)
+
+	type WritableInfo struct {
+		LogLevel string
+	}
+
+	require.NoError(t, client.PutConfigurationValue("Writable/LogLevel", []byte("INFO")))
+
+	updateChannel := make(chan interface{})
+	errorChannel := make(chan error)
+
+	// no message bus, so the keys are polled for changes
+	client.WatchForChanges(updateChannel, errorChannel, &WritableInfo{}, "Writable", nil)
+	defer client.StopWatching()
+
+	receive := func() interface{} {
+		select {
+		case update := <-updateChannel:
+			return update
+		case err := <-errorChannel:
+			require.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting on configuration update")
+		}
+		return nil
+	}
+
+	// the first update is nil for go-mod-bootstrap to ignore it
+	assert.Nil(t, receive())
+
+	require.NoError(t, client.PutConfigurationValue("Writable/LogLevel", []byte("DEBUG")))
+
+	update := receive()
+	require.IsType(t, &WritableInfo{}, update)
+	assert.Equal(t, "DEBUG", update.(*WritableInfo).LogLevel)
+
+	// keys outside of the watched key don't trigger an update
+	require.NoError(t, client.PutConfigurationValue("Logging/File", []byte("NONE")))
+	select {
+	case update := <-updateChannel:
+		t.Fatalf("unexpected update: %v", update)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestNewKeeperClientMessageBus(t *testing.T) {
+	expected := &models.MessageBusInfo{Type: "mqtt", Protocol: "tcp", Host: "localhost", Port: 1883}
+
+	tests := []struct {
+		name          string
+		optional      map[string]any
+		expected      *models.MessageBusInfo
+		expectedError bool
+	}{
+		{"None", nil, nil, false},
+		{"MessageBusInfo", map[string]any{"MessageBus": *expected}, expected, false},
+		{"MessageBusInfo pointer", map[string]any{"MessageBus": expected}, expected, false},
+		{"Map", map[string]any{"MessageBus": map[string]any{"Type": "mqtt", "Protocol": "tcp", "Host": "localhost", "Port": "1883"}}, expected, false},
+		{"Missing Host", map[string]any{"MessageBus": map[string]any{"Type": "mqtt"}}, nil, true},
+		{"Invalid", map[string]any{"MessageBus": "mqtt://localhost:1883"}, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, err := NewKeeperClient(types.ServiceConfig{Host: testHost, Port: port, Optional: test.optional})
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, client.messageBusInfo)
+		})
+	}
+}
+
+func TestWatchForChangesOwnMessageBus(t *testing.T) {
+	config := types.ServiceConfig{
+		Host:     testHost,
+		Port:     port,
+		BasePath: getUniqueServiceName(),
+		Optional: map[string]any{
+			"MessageBus": models.MessageBusInfo{
+				Type:     "mqtt",
+				Protocol: "tcp",
+				Host:     "localhost",
+				Port:     1883,
+				Optional: map[string]string{"ClientId": "core-data"},
+			},
+		},
+	}
+	client, err := NewKeeperClient(config)
+	require.NoError(t, err)
+
+	// delete the configuration created
+	defer reset(t, client)
+
+	var busConfigs []msgTypes.MessageBusConfig
+	disconnected := make(chan struct{})
+	client.newMessageClient = func(config msgTypes.MessageBusConfig) (messaging.MessageClient, error) {
+		busConfigs = append(busConfigs, config)
+		messageBus := &messagingMocks.MessageClient{}
+		messageBus.On("Connect").Return(nil)
+		messageBus.On("Subscribe", mock.Anything, mock.Anything).Return(nil)
+		messageBus.On("Disconnect").Run(func(args mock.Arguments) {
+			close(disconnected)
+		}).Return(nil)
+		return messageBus, nil
+	}
+
+	updateChannel := make(chan interface{})
+	errorChannel := make(chan error)
+
+	client.WatchForChanges(updateChannel, errorChannel, &LoggingInfo{}, "Writable", nil)
+
+	select {
+	case update := <-updateChannel:
+		assert.Nil(t, update)
+	case err := <-errorChannel:
+		t.Fatalf("received WatchForChanges error: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting on first update")
+	}
+
+	require.Len(t, busConfigs, 1)
+	assert.Equal(t, "mqtt", busConfigs[0].Type)
+	assert.Equal(t, msgTypes.HostInfo{Host: "localhost", Port: 1883, Protocol: "tcp"}, busConfigs[0].Broker)
+	assert.Equal(t, "true", busConfigs[0].Optional["AutoReconnect"])
+	assert.Equal(t, "core-data-1", busConfigs[0].Optional["ClientId"])
+	// the configured options are left untouched
+	assert.Equal(t, "core-data", client.messageBusInfo.Optional["ClientId"])
+
+	// the message bus is disconnected once the watch has stopped
+	client.StopWatching()
+	select {
+	case <-disconnected:
+	default:
+		t.Fatal("message bus owned by the watch is not disconnected")
+	}
+
+	t.Run("Connect failure", func(t *testing.T) {
+		client.newMessageClient = func(config msgTypes.MessageBusConfig) (messaging.MessageClient, error) {
+			messageBus := &messagingMocks.MessageClient{}
+			messageBus.On("Connect").Return(errors.New("connection refused"))
+			return messageBus, nil
+		}
+
+		// the error is sent once the caller reads from the channels
+		errorChannel := make(chan error)
+		client.WatchForChanges(updateChannel, errorChannel, &LoggingInfo{}, "Writable", nil)
+
+		select {
+		case err := <-errorChannel:
+			assert.ErrorContains(t, err, "connection refused")
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting on connect error")
+		}
+	})
+}
+
+func TestWatchForChangesResubscribe(t *testing.T) {
+	config := types.ServiceConfig{
+		Host:     testHost,
+		Port:     port,
+		BasePath: getUniqueServiceName(),
+		Retry:    types.RetryInfo{InitialBackoff: 10 * time.Millisecond},
+		Optional: map[string]any{
+			"MessageBus": models.MessageBusInfo{Type: "mqtt", Protocol: "tcp", Host: "localhost", Port: 1883},
+		},
+	}
+	client, err := NewKeeperClient(config)
+	require.NoError(t, err)
+
+	// delete the configuration created
+	defer reset(t, client)
+	defer client.StopWatching()
+
+	type WritableInfo struct {
+		LogLevel string
+	}
+
+	require.NoError(t, client.PutConfigurationValue("Writable/LogLevel", []byte("INFO")))
+
+	receive := func(updateChannel chan interface{}, errorChannel chan error) interface{} {
+		select {
+		case update := <-updateChannel:
+			return update
+		case err := <-errorChannel:
+			t.Fatalf("received WatchForChanges error: %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting on update")
+		}
+		return nil
+	}
+	expectDegraded := func(errorChannel chan error, cause error) {
+		select {
+		case err := <-errorChannel:
+			var degradedErr *types.DegradedError
+			require.ErrorAs(t, err, &degradedErr)
+			assert.Equal(t, "Writable", degradedErr.Key)
+			assert.ErrorIs(t, err, cause)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting on degraded error")
+		}
+	}
+	expectNoUpdate := func(updateChannel chan interface{}) {
+		select {
+		case update := <-updateChannel:
+			t.Fatalf("unexpected update: %v", update)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	t.Run("Message bus given by the caller", func(t *testing.T) {
+		subscribed := make(chan chan error, 1)
+		messageBus := &messagingMocks.MessageClient{}
+		messageBus.On("Subscribe", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			subscribed <- args.Get(1).(chan error)
+		}).Return(nil)
+
+		updateChannel := make(chan interface{})
+		errorChannel := make(chan error)
+
+		client.WatchForChanges(updateChannel, errorChannel, &WritableInfo{}, "Writable", messageBus)
+		watchErrors := <-subscribed
+		assert.Nil(t, receive(updateChannel, errorChannel))
+
+		// the change is made while the subscription may be lost
+		require.NoError(t, client.PutConfigurationValue("Writable/LogLevel", []byte("DEBUG")))
+		connectionLost := errors.New("connection lost")
+		watchErrors <- connectionLost
+		expectDegraded(errorChannel, connectionLost)
+
+		// the configuration is read again
+		update := receive(updateChannel, errorChannel)
+		require.IsType(t, &WritableInfo{}, update)
+		assert.Equal(t, "DEBUG", update.(*WritableInfo).LogLevel)
+
+		// no update is sent if nothing has changed meanwhile
+		watchErrors <- connectionLost
+		expectDegraded(errorChannel, connectionLost)
+		expectNoUpdate(updateChannel)
+
+		// the message bus given by the caller, which may be shared, is neither subscribed to nor connected again
+		messageBus.AssertNumberOfCalls(t, "Subscribe", 1)
+		messageBus.AssertNotCalled(t, "Unsubscribe", mock.Anything)
+		messageBus.AssertNotCalled(t, "Connect")
+	})
+
+	t.Run("Repeated non fatal errors", func(t *testing.T) {
+		// the message bus created by the watch delivers the messages to all of its active subscriptions
+		var lock sync.Mutex
+		var subscriptions []chan<- msgTypes.MessageEnvelope
+		subscribed := make(chan chan error, 10)
+		client.newMessageClient = func(config msgTypes.MessageBusConfig) (messaging.MessageClient, error) {
+			messageBus := &messagingMocks.MessageClient{}
+			messageBus.On("Connect").Return(nil)
+			messageBus.On("Disconnect").Return(nil)
+			messageBus.On("Subscribe", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				lock.Lock()
+				subscriptions = append(subscriptions, args.Get(0).([]msgTypes.TopicChannel)[0].Messages)
+				lock.Unlock()
+				subscribed <- args.Get(1).(chan error)
+			}).Return(nil)
+			messageBus.On("Unsubscribe", mock.Anything).Run(func(args mock.Arguments) {
+				lock.Lock()
+				subscriptions = nil
+				lock.Unlock()
+			}).Return(nil)
+			return messageBus, nil
+		}
+
+		updateChannel := make(chan interface{})
+		errorChannel := make(chan error)
+
+		client.WatchForChanges(updateChannel, errorChannel, &WritableInfo{}, "Writable", nil)
+		watchErrors := <-subscribed
+		assert.Nil(t, receive(updateChannel, errorChannel))
+
+		// errors concerning single messages, the subscription still being alive
+		unmarshalFailed := errors.New("unable to unmarshal message")
+		for i := 0; i < 3; i++ {
+			watchErrors <- unmarshalFailed
+			expectDegraded(errorChannel, unmarshalFailed)
+			watchErrors = <-subscribed
+		}
+
+		lock.Lock()
+		active := slices.Clone(subscriptions)
+		lock.Unlock()
+		assert.Len(t, active, 1, "the subscription must be dropped before subscribing again")
+
+		require.NoError(t, client.PutConfigurationValue("Writable/LogLevel", []byte("WARN")))
+		payload, _ := json.Marshal(dtos.KV{Key: client.fullPath("Writable/LogLevel"), Value: "WARN"})
+		for _, messages := range active {
+			go func(messages chan<- msgTypes.MessageEnvelope) {
+				messages <- msgTypes.MessageEnvelope{ContentType: httpUtils.ContentTypeJSON, Payload: payload}
+			}(messages)
+		}
+
+		// the update is delivered once
+		update := receive(updateChannel, errorChannel)
+		require.IsType(t, &WritableInfo{}, update)
+		assert.Equal(t, "WARN", update.(*WritableInfo).LogLevel)
+		expectNoUpdate(updateChannel)
+	})
+}
+
+func TestWatchForChangesIncremental(t *testing.T) {
+	if mockCoreKeeper == nil {
+		t.Skip("requests can only be counted by the mock Core Keeper")
+	}
+
+	client := makeCoreKeeperClient(getUniqueServiceName())
+
+	// delete the configuration created
+	defer reset(t, client)
+	defer client.StopWatching()
+
+	type WritableInfo struct {
+		LogLevel        string
+		Timeout         string
+		InsecureSecrets map[string]any
+	}
+
+	require.NoError(t, client.PutConfigurationValue("Writable/LogLevel", []byte("INFO")))
+	require.NoError(t, client.PutConfigurationValue("Writable/Timeout", []byte("5s")))
+
+	var messages chan<- msgTypes.MessageEnvelope
+	messageBus := &messagingMocks.MessageClient{}
+	messageBus.On("Subscribe", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		messages = args.Get(0).([]msgTypes.TopicChannel)[0].Messages
+	}).Return(nil)
+	messageBus.On("Disconnect").Return(nil)
+
+	updateChannel := make(chan interface{})
+	errorChannel := make(chan error)
+
+	client.WatchForChanges(updateChannel, errorChannel, &WritableInfo{}, "Writable", messageBus)
+	require.NotNil(t, messages)
+
+	receive := func() *WritableInfo {
+		select {
+		case update := <-updateChannel:
+			if update == nil {
+				return nil
+			}
+			require.IsType(t, &WritableInfo{}, update)
+			return update.(*WritableInfo)
+		case err := <-errorChannel:
+			require.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting on configuration update")
+		}
+		return nil
+	}
+
+	publish := func(key string, value any) {
+		payload, _ := json.Marshal(dtos.KV{Key: client.fullPath(key), Value: value})
+		messages <- msgTypes.MessageEnvelope{ContentType: httpUtils.ContentTypeJSON, Payload: payload}
+	}
+
+	assert.Nil(t, receive())
+	getRequests := mockCoreKeeper.getRequests.Load()
+
+	// a single value is applied to the local copy without reading the keys again
+	publish("Writable/LogLevel", "DEBUG")
+	update := receive()
+	require.NotNil(t, update)
+	assert.Equal(t, "DEBUG", update.LogLevel)
+	assert.Equal(t, "5s", update.Timeout)
+	assert.Equal(t, getRequests, mockCoreKeeper.getRequests.Load())
+
+	// an unchanged value doesn't trigger an update
+	publish("Writable/LogLevel", "DEBUG")
+	select {
+	case update := <-updateChannel:
+		t.Fatalf("unexpected update: %v", update)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// a change which isn't a single value is read from Core Keeper
+	require.NoError(t, client.PutConfigurationValue("Writable/InsecureSecrets/DB/Path", []byte("redisdb")))
+	publish("Writable/InsecureSecrets", map[string]any{"DB": map[string]any{"Path": "redisdb"}})
+	update = receive()
+	require.NotNil(t, update)
+	assert.Equal(t, map[string]any{"DB": map[string]any{"Path": "redisdb"}}, update.InsecureSecrets)
+	assert.Greater(t, mockCoreKeeper.getRequests.Load(), getRequests)
+}
+
+func TestApplyChange(t *testing.T) {
+	client := makeCoreKeeperClient("edgex/core-data")
+	keyPrefix := "edgex/core-data/Writable"
+
+	tests := []struct {
+		name            string
+		subtree         map[string]string
+		change          dtos.KV
+		expectedChanged bool
+		expectedApplied bool
+		expected        map[string]string
+	}{
+		{"Modified", map[string]string{"Writable/LogLevel": "INFO"}, dtos.KV{Key: "edgex/core-data/Writable/LogLevel", Value: "DEBUG"}, true, true, map[string]string{"Writable/LogLevel": "DEBUG"}},
+		{"Added", map[string]string{"Writable/LogLevel": "INFO"}, dtos.KV{Key: "edgex/core-data/Writable/Timeout", Value: 5.0}, true, true, map[string]string{"Writable/LogLevel": "INFO", "Writable/Timeout": "5"}},
+		{"Unchanged", map[string]string{"Writable/LogLevel": "INFO"}, dtos.KV{Key: "edgex/core-data/Writable/LogLevel", Value: "INFO"}, false, true, map[string]string{"Writable/LogLevel": "INFO"}},
+		{"Unknown subtree", nil, dtos.KV{Key: "edgex/core-data/Writable/LogLevel", Value: "DEBUG"}, false, false, nil},
+		{"Not a single value", map[string]string{}, dtos.KV{Key: "edgex/core-data/Writable/DB", Value: map[string]any{"Host": "localhost"}}, false, false, map[string]string{}},
+		{"Whole subtree", map[string]string{}, dtos.KV{Key: keyPrefix, Value: "DEBUG"}, false, false, map[string]string{}},
+		{"Value is a directory", map[string]string{"Writable/DB/Host": "localhost"}, dtos.KV{Key: "edgex/core-data/Writable/DB", Value: "redis"}, false, false, map[string]string{"Writable/DB/Host": "localhost"}},
+		{"Directory is a value", map[string]string{"Writable/DB": "redis"}, dtos.KV{Key: "edgex/core-data/Writable/DB/Host", Value: "localhost"}, false, false, map[string]string{"Writable/DB": "redis"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			changed, applied := client.applyChange(test.subtree, keyPrefix, test.change)
+			assert.Equal(t, test.expectedChanged, changed)
+			assert.Equal(t, test.expectedApplied, applied)
+			assert.Equal(t, test.expected, test.subtree)
+		})
+	}
+}
+
+func TestWatchForChangesDebounce(t *testing.T) {
+	client := makeCoreKeeperClient(getUniqueServiceName())
+	client.debounceWindow = 100 * time.Millisecond
+
+	// delete the configuration created
+	defer reset(t, client)
+	defer client.StopWatching()
+
+	type WritableInfo struct {
+		LogLevel string
+		Timeout  string
+	}
+
+	require.NoError(t, client.PutConfigurationValue("Writable/LogLevel", []byte("INFO")))
+
+	var messages chan<- msgTypes.MessageEnvelope
+	messageBus := &messagingMocks.MessageClient{}
+	messageBus.On("Subscribe", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		messages = args.Get(0).([]msgTypes.TopicChannel)[0].Messages
+	}).Return(nil)
+	messageBus.On("Disconnect").Return(nil)
+
+	updateChannel := make(chan interface{})
+	errorChannel := make(chan error)
+
+	client.WatchForChanges(updateChannel, errorChannel, &WritableInfo{}, "Writable", messageBus)
+	require.NotNil(t, messages)
+
+	publish := func(key string, value any) {
+		payload, _ := json.Marshal(dtos.KV{Key: client.fullPath(key), Value: value})
+		messages <- msgTypes.MessageEnvelope{ContentType: httpUtils.ContentTypeJSON, Payload: payload}
+	}
+
+	// the first update isn't held back
+	select {
+	case update := <-updateChannel:
+		assert.Nil(t, update)
+	case <-time.After(client.debounceWindow / 2):
+		t.Fatal("first update is held back")
+	}
+
+	// a burst of changes results in a single update
+	publish("Writable/LogLevel", "DEBUG")
+	publish("Writable/Timeout", "5s")
+	publish("Writable/LogLevel", "TRACE")
+
+	select {
+	case update := <-updateChannel:
+		require.IsType(t, &WritableInfo{}, update)
+		assert.Equal(t, &WritableInfo{LogLevel: "TRACE", Timeout: "5s"}, update)
+	case err := <-errorChannel:
+		t.Fatalf("received WatchForChanges error: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting on configuration update")
+	}
+
+	select {
+	case update := <-updateChannel:
+		t.Fatalf("unexpected update: %v", update)
+	case <-time.After(2 * client.debounceWindow):
+	}
+}
+
+func TestTLS(t *testing.T) {
+	server := NewMockCoreKeeper().StartTLS()
+	defer server.Close()
+
+	serverUrl, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	serverPort, err := strconv.Atoi(serverUrl.Port())
+	require.NoError(t, err)
+
+	makeClient := func(tls types.TLSInfo) *keeperClient {
+		client, err := NewKeeperClient(types.ServiceConfig{
+			Protocol: "https",
+			Host:     serverUrl.Hostname(),
+			Port:     serverPort,
+			BasePath: getUniqueServiceName(),
+			TLS:      tls,
+		})
+		require.NoError(t, err)
+		return client
+	}
+
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	client := makeClient(types.TLSInfo{CAPEM: caPEM, ServerName: "example.com"})
+
+	assert.True(t, client.IsAlive())
+	require.NoError(t, client.PutConfigurationValue("Port", []byte("8000")))
+	value, err := client.GetConfigurationValue("Port")
+	require.NoError(t, err)
+	assert.Equal(t, "8000", string(value))
+
+	t.Run("Untrusted", func(t *testing.T) {
+		client := makeClient(types.TLSInfo{})
+
+		assert.False(t, client.IsAlive())
+		_, err := client.GetConfigurationValue("Port")
+		require.Error(t, err)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := NewKeeperClient(types.ServiceConfig{Host: "localhost", Port: 59883, TLS: types.TLSInfo{MinVersion: "0.9"}})
+		require.Error(t, err)
+	})
+}
+
+func TestAccessToken(t *testing.T) {
+	goodToken := "GoodToken"
+	badToken := "BadToken"
+
+	mockKeeper := NewMockCoreKeeper()
+	server := mockKeeper.Start()
+	defer server.Close()
+
+	serverUrl, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	serverPort, err := strconv.Atoi(serverUrl.Port())
+	require.NoError(t, err)
+
+	var renewals atomic.Int32
+	makeClient := func(accessToken string, renewedToken string, renewalErr error) *keeperClient {
+		renewals.Store(0)
+		config := types.ServiceConfig{
+			Host:        serverUrl.Hostname(),
+			Port:        serverPort,
+			BasePath:    getUniqueServiceName(),
+			AccessToken: accessToken,
+		}
+		if renewedToken != "" || renewalErr != nil {
+			config.GetAccessToken = func() (string, error) {
+				renewals.Add(1)
+				return renewedToken, renewalErr
+			}
+		}
+
+		client, err := NewKeeperClient(config)
+		require.NoError(t, err)
+		return client
+	}
+
+	mockKeeper.SetExpectedAccessToken(goodToken)
+
+	t.Run("Valid token", func(t *testing.T) {
+		client := makeClient(goodToken, "", nil)
+
+		assert.True(t, client.IsAlive())
+		require.NoError(t, client.PutConfigurationValue("Port", []byte("8000")))
+		value, err := client.GetConfigurationValue("Port")
+		require.NoError(t, err)
+		assert.Equal(t, "8000", string(value))
+	})
+
+	t.Run("Renewed token", func(t *testing.T) {
+		client := makeClient(badToken, goodToken, nil)
+
+		require.NoError(t, client.PutConfigurationValue("Port", []byte("8000")))
+		assert.Equal(t, int32(1), renewals.Load())
+
+		// the renewed token is used from then on
+		_, err := client.GetConfigurationValue("Port")
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), renewals.Load())
+	})
+
+	t.Run("No callback", func(t *testing.T) {
+		client := makeClient(badToken, "", nil)
+
+		assert.False(t, client.IsAlive())
+		_, err := client.GetConfigurationValue("Port")
+		var authErr *types.AuthError
+		require.ErrorAs(t, err, &authErr)
+		assert.Equal(t, http.StatusUnauthorized, authErr.StatusCode)
+	})
+
+	t.Run("Renewal fails", func(t *testing.T) {
+		renewalErr := errors.New("secret store unavailable")
+		client := makeClient(badToken, "", renewalErr)
+
+		_, err := client.GetConfigurationValue("Port")
+		var authErr *types.AuthError
+		require.ErrorAs(t, err, &authErr)
+		assert.ErrorIs(t, err, renewalErr)
+		assert.Equal(t, int32(1), renewals.Load())
+	})
+
+	t.Run("Renewed token rejected", func(t *testing.T) {
+		client := makeClient(badToken, "StillBadToken", nil)
+
+		_, err := client.GetConfigurationValue("Port")
+		var authErr *types.AuthError
+		require.ErrorAs(t, err, &authErr)
+		assert.Equal(t, http.StatusUnauthorized, authErr.StatusCode)
+		assert.Equal(t, int32(1), renewals.Load(), "request must only be made again once")
+	})
+}
+
+func TestAccessTokenRenewedAheadOfExpiry(t *testing.T) {
+	mockKeeper := NewMockCoreKeeper()
+	server := mockKeeper.Start()
+	defer server.Close()
+
+	serverUrl, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	serverPort, err := strconv.Atoi(serverUrl.Port())
+	require.NoError(t, err)
+
+	// the first token expires quickly, the renewed one doesn't
+	var renewals atomic.Int32
+	client, err := NewKeeperClient(types.ServiceConfig{
+		Host:     serverUrl.Hostname(),
+		Port:     serverPort,
+		BasePath: getUniqueServiceName(),
+		GetAccessTokenWithTTL: func() (string, time.Duration, error) {
+			if renewals.Add(1) == 1 {
+				return "ShortLivedToken", 100 * time.Millisecond, nil
+			}
+			return "GoodToken", time.Hour, nil
+		},
+	})
+	require.NoError(t, err)
+	defer client.tokenProvider.Stop()
+
+	require.Eventually(t, func() bool {
+		return renewals.Load() == 2
+	}, time.Second, 10*time.Millisecond)
+
+	// no request has to be rejected for the renewed token to be used
+	mockKeeper.SetExpectedAccessToken("GoodToken")
+	require.NoError(t, client.PutConfigurationValue("Port", []byte("8000")))
+	assert.Equal(t, int32(2), renewals.Load())
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	mockKeeper := NewMockCoreKeeper()
+	server := mockKeeper.Start()
+	defer server.Close()
+
+	serverUrl, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	serverPort, err := strconv.Atoi(serverUrl.Port())
+	require.NoError(t, err)
+
+	var renewals atomic.Int32
+	client, err := NewKeeperClient(types.ServiceConfig{
+		Host:        serverUrl.Hostname(),
+		Port:        serverPort,
+		BasePath:    getUniqueServiceName(),
+		AccessToken: "BadToken",
+		GetAccessToken: func() (string, error) {
+			renewals.Add(1)
+			// leave time for the other rejected requests to join the renewal
+			time.Sleep(50 * time.Millisecond)
+			return "GoodToken", nil
+		},
+	})
+	require.NoError(t, err)
+	mockKeeper.SetExpectedAccessToken("GoodToken")
+
+	const goroutines = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*goroutines)
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := "Key" + strconv.Itoa(i) + "/Value"
+			if err := client.PutConfigurationValue(key, []byte(strconv.Itoa(i))); err != nil {
+				errs <- err
+				return
+			}
+			value, err := client.GetConfigurationValue(key)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if string(value) != strconv.Itoa(i) {
+				errs <- fmt.Errorf("unexpected value '%s' for %s", value, key)
+			}
+			client.StopWatching()
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), renewals.Load(), "simultaneous rejections must renew the access token once")
+}
+
+func TestCloseStopsAccessTokenRenewal(t *testing.T) {
+	var renewals atomic.Int32
+	client, err := NewKeeperClient(types.ServiceConfig{
+		Host:     testHost,
+		Port:     port,
+		BasePath: getUniqueServiceName(),
+		GetAccessTokenWithTTL: func() (string, time.Duration, error) {
+			renewals.Add(1)
+			return "ShortLivedToken", 40 * time.Millisecond, nil
+		},
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return renewals.Load() >= 3
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, client.Close())
+	closedRenewals := renewals.Load()
+
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, closedRenewals, renewals.Load(), "the access token must not be renewed once the client is closed")
+}
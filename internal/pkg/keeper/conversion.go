@@ -43,3 +43,11 @@ func convertInterfaceToPairs(path string, interfaceMap interface{}) []*pair {
 
 	return pairs
 }
+
+func pairKeys(pairs []*pair) []string {
+	keys := make([]string, 0, len(pairs))
+	for _, keyValue := range pairs {
+		keys = append(keys, keyValue.Key)
+	}
+	return keys
+}
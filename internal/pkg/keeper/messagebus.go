@@ -0,0 +1,90 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package keeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/keeper/models"
+	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/retry"
+
+	"github.com/edgexfoundry/go-mod-messaging/v3/messaging"
+	msgTypes "github.com/edgexfoundry/go-mod-messaging/v3/pkg/types"
+)
+
+const (
+	// messageBusKey is the key of ServiceConfig.Optional holding the MessageBusInfo of the message bus
+	// on which Core Keeper publishes the configuration changes
+	messageBusKey    = "MessageBus"
+	autoReconnectKey = "AutoReconnect"
+	clientIdKey      = "ClientId"
+)
+
+// parseMessageBusInfo gets the message bus information from the optional properties of the configuration provider,
+// either as a MessageBusInfo or as a map of its fields. Nil is returned if no message bus is configured.
+func parseMessageBusInfo(optional map[string]any) (*models.MessageBusInfo, error) {
+	value, ok := optional[messageBusKey]
+	if !ok || value == nil {
+		return nil, nil
+	}
+
+	var info models.MessageBusInfo
+	if err := mapstructure.WeakDecode(value, &info); err != nil {
+		return nil, fmt.Errorf("invalid %s in the optional properties of the configuration provider: %w", messageBusKey, err)
+	}
+	if info.Type == "" || info.Host == "" {
+		return nil, fmt.Errorf("the Type and Host of %s must be set in the optional properties of the configuration provider", messageBusKey)
+	}
+
+	return &info, nil
+}
+
+// newMessageBus creates a message client connected to the message bus configured in the optional properties.
+// The message client is owned by the watch it is created for, which disconnects it once it has stopped.
+func (client *keeperClient) newMessageBus(ctx context.Context) (messaging.MessageClient, error) {
+	info := client.messageBusInfo
+	if info == nil {
+		return nil, errors.New("unable to use MessageClient to watch for configuration changes")
+	}
+
+	optional := make(map[string]string, len(info.Optional)+1)
+	maps.Copy(optional, info.Optional)
+	// the MQTT client only reconnects after the connection is lost when asked to
+	if _, ok := optional[autoReconnectKey]; !ok {
+		optional[autoReconnectKey] = "true"
+	}
+	// every watch has its own connection, which the broker would drop if they shared the same client id
+	if clientId := optional[clientIdKey]; clientId != "" {
+		optional[clientIdKey] = fmt.Sprintf("%s-%d", clientId, client.messageBusCount.Add(1))
+	}
+
+	messageBus, err := client.newMessageClient(msgTypes.MessageBusConfig{
+		Broker: msgTypes.HostInfo{
+			Host:     info.Host,
+			Port:     info.Port,
+			Protocol: info.Protocol,
+		},
+		Type:     info.Type,
+		Optional: optional,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create MessageClient to watch for configuration changes: %w", err)
+	}
+
+	err = retry.Do(ctx, client.retryPolicy, func(error) bool { return true }, func(context.Context) error {
+		return messageBus.Connect()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect MessageClient to watch for configuration changes: %w", err)
+	}
+
+	return messageBus, nil
+}
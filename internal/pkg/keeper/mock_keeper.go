@@ -13,6 +13,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/keeper/api"
 	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/keeper/dtos"
@@ -20,21 +22,54 @@ import (
 )
 
 type MockCoreKeeper struct {
+	// lock guards keyValueStore, so that the mock can serve concurrent requests
+	lock          sync.Mutex
 	keyValueStore map[string]dtos.KV
+	// getRequests counts the requests getting keys
+	getRequests atomic.Int64
+	// expectedAccessToken is the bearer token required by every request, if not empty
+	expectedAccessToken atomic.Value
 }
 
 func NewMockCoreKeeper() *MockCoreKeeper {
-	return &MockCoreKeeper{
+	mock := &MockCoreKeeper{
 		keyValueStore: make(map[string]dtos.KV),
 	}
+	mock.expectedAccessToken.Store("")
+
+	return mock
 }
 
 func (mock *MockCoreKeeper) Reset() {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+
 	mock.keyValueStore = make(map[string]dtos.KV)
 }
 
 func (mock *MockCoreKeeper) Start() *httptest.Server {
-	testMockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+	return httptest.NewServer(mock.handler())
+}
+
+// StartTLS starts the mock Core Keeper serving HTTPS with the certificate of httptest
+func (mock *MockCoreKeeper) StartTLS() *httptest.Server {
+	return httptest.NewTLSServer(mock.handler())
+}
+
+func (mock *MockCoreKeeper) handler() http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		// the request is rejected like an API gateway does, without a JSON error response
+		if token := mock.expectedAccessToken.Load().(string); len(token) > 0 {
+			if request.Header.Get(httpUtils.Authorization) != httpUtils.BearerPrefix+token {
+				writer.WriteHeader(http.StatusUnauthorized)
+				_, _ = writer.Write([]byte("Unauthorized"))
+				return
+			}
+		}
+
+		mock.lock.Lock()
+		defer mock.lock.Unlock()
+
 		if strings.Contains(request.URL.Path, api.ApiKVRoute) {
 			key := strings.Replace(request.URL.Path, api.ApiKVRoute+"/", "", 1)
 
@@ -60,6 +95,7 @@ func (mock *MockCoreKeeper) Start() *httptest.Server {
 					mock.updateKVStore(key, addKeysRequest.Value)
 				}
 			case "GET":
+				mock.getRequests.Add(1)
 				query := request.URL.Query()
 				_, allKeysRequested := query[api.KeyOnly]
 
@@ -96,6 +132,33 @@ func (mock *MockCoreKeeper) Start() *httptest.Server {
 				}
 				writer.Header().Set("Content-Type", "application/json")
 
+				if err := json.NewEncoder(writer).Encode(resp); err != nil {
+					log.Printf("error writing data response: %s", err.Error())
+				}
+			case "DELETE":
+				_, prefixMatch := request.URL.Query()[api.PrefixMatch]
+
+				var keys []dtos.KeyOnly
+				for k := range mock.keyValueStore {
+					if k == key || (prefixMatch && strings.HasPrefix(k, key+api.KeyDelimiter)) {
+						delete(mock.keyValueStore, k)
+						keys = append(keys, dtos.KeyOnly(k))
+					}
+				}
+
+				var resp interface{}
+				if len(keys) == 0 {
+					resp = httpUtils.ErrorResponse{
+						Message:    fmt.Sprintf("query key %s not found", key),
+						StatusCode: http.StatusNotFound,
+					}
+					writer.WriteHeader(http.StatusNotFound)
+				} else {
+					resp = dtos.MultiKeyResponse{Keys: keys}
+					writer.WriteHeader(http.StatusOK)
+				}
+				writer.Header().Set("Content-Type", "application/json")
+
 				if err := json.NewEncoder(writer).Encode(resp); err != nil {
 					log.Printf("error writing data response: %s", err.Error())
 				}
@@ -107,9 +170,15 @@ func (mock *MockCoreKeeper) Start() *httptest.Server {
 
 			}
 		}
-	}))
+	})
+}
+
+func (mock *MockCoreKeeper) SetExpectedAccessToken(token string) {
+	mock.expectedAccessToken.Store(token)
+}
 
-	return testMockServer
+func (mock *MockCoreKeeper) ClearExpectedAccessToken() {
+	mock.expectedAccessToken.Store("")
 }
 
 func (mock *MockCoreKeeper) checkForPrefix(prefix string) ([]dtos.KV, bool) {
@@ -7,6 +7,7 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -21,6 +22,11 @@ type ErrorResponse struct {
 	StatusCode int    `json:"statusCode"`
 }
 
+// Error returns the message of the error response, so it can be returned as the error of a failed request
+func (e ErrorResponse) Error() string {
+	return e.Message
+}
+
 // Helper method to get the body from the response after making the request
 func getBody(resp *http.Response) ([]byte, error) {
 	body, err := io.ReadAll(resp.Body)
@@ -30,16 +36,21 @@ func getBody(resp *http.Response) ([]byte, error) {
 	return body, nil
 }
 
-// Helper method to make the request and return the response
-func makeRequest(req *http.Request) (*http.Response, error) {
-	client := &http.Client{}
+// Helper method to make the request with the client and return the response
+func makeRequest(client *http.Client, req *http.Request) (*http.Response, error) {
+	if client == nil {
+		client = &http.Client{}
+	}
 	resp, err := client.Do(req)
 	if err != nil {
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			return nil, fmt.Errorf("request to %s was not completed: %w", req.URL.Host, ctxErr)
+		}
 		var netErr *net.OpError
 		if errors.As(err, &netErr) {
-			return nil, fmt.Errorf("%s cannot be reached, this service is not available", req.URL.Host)
+			return nil, fmt.Errorf("%s cannot be reached, this service is not available: %w", req.URL.Host, err)
 		} else {
-			return nil, errors.New("failed to send a http request")
+			return nil, fmt.Errorf("failed to send a http request: %w", err)
 		}
 	}
 	if resp == nil {
@@ -48,7 +59,7 @@ func makeRequest(req *http.Request) (*http.Response, error) {
 	return resp, nil
 }
 
-func createRequest(httpMethod string, baseUrl string, requestPath string, requestParams url.Values) (*http.Request, error) {
+func createRequest(ctx context.Context, httpMethod string, baseUrl string, requestPath string, requestParams url.Values) (*http.Request, error) {
 	u, err := url.Parse(baseUrl)
 	if err != nil {
 		return nil, err
@@ -57,7 +68,7 @@ func createRequest(httpMethod string, baseUrl string, requestPath string, reques
 	if requestParams != nil {
 		u.RawQuery = requestParams.Encode()
 	}
-	req, err := http.NewRequest(httpMethod, u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, httpMethod, u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -66,10 +77,10 @@ func createRequest(httpMethod string, baseUrl string, requestPath string, reques
 
 // sendRequest will make a request with raw data to the specified URL.
 // It returns the body as a byte array if successful and an error otherwise.
-func sendRequest(req *http.Request) ([]byte, ErrorResponse, error) {
+func sendRequest(client *http.Client, req *http.Request) ([]byte, ErrorResponse, error) {
 	var errResponse ErrorResponse
 
-	resp, err := makeRequest(req)
+	resp, err := makeRequest(client, req)
 	if err != nil {
 		return nil, errResponse, err
 	}
@@ -86,16 +97,16 @@ func sendRequest(req *http.Request) ([]byte, ErrorResponse, error) {
 		return bodyBytes, errResponse, nil
 	}
 
-	// Handle error response
-	err = json.Unmarshal(bodyBytes, &errResponse)
-	if err != nil {
-		return nil, errResponse, err
+	// Handle error response, which might not be JSON if returned by a proxy, i.e. an API gateway
+	if err = json.Unmarshal(bodyBytes, &errResponse); err != nil {
+		errResponse.Message = fmt.Sprintf("request to %s failed with status %s", req.URL.Host, resp.Status)
 	}
+	errResponse.StatusCode = resp.StatusCode
 
 	return nil, errResponse, nil
 }
 
-func createRequestWithRawData(httpMethod string, baseUrl string, requestPath string, requestParams url.Values, data interface{}) (*http.Request, error) {
+func createRequestWithRawData(ctx context.Context, httpMethod string, baseUrl string, requestPath string, requestParams url.Values, data interface{}) (*http.Request, error) {
 	u, err := url.Parse(baseUrl)
 	if err != nil {
 		return nil, fmt.Errorf("fail to parse baseUrl, err: %v", err)
@@ -110,7 +121,7 @@ func createRequestWithRawData(httpMethod string, baseUrl string, requestPath str
 		return nil, fmt.Errorf("failed to encode input data to JSON, err: %v", err)
 	}
 
-	req, err := http.NewRequest(httpMethod, u.String(), bytes.NewReader(jsonEncodedData))
+	req, err := http.NewRequestWithContext(ctx, httpMethod, u.String(), bytes.NewReader(jsonEncodedData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create a http request, err: %v", err)
 	}
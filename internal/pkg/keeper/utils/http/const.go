@@ -8,4 +8,6 @@ package http
 const (
 	ContentType     = "Content-Type"
 	ContentTypeJSON = "application/json"
+	Authorization   = "Authorization"
+	BearerPrefix    = "Bearer "
 )
@@ -6,20 +6,21 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 )
 
-// GetRequest makes the get request and return the body
-func GetRequest(returnValuePointer interface{}, baseUrl string, requestPath string, requestParams url.Values) (ErrorResponse, error) {
-	req, err := createRequest(http.MethodGet, baseUrl, requestPath, requestParams)
+// GetRequest makes the get request with the client and return the body
+func GetRequest(ctx context.Context, client *http.Client, returnValuePointer interface{}, baseUrl string, requestPath string, requestParams url.Values) (ErrorResponse, error) {
+	req, err := createRequest(ctx, http.MethodGet, baseUrl, requestPath, requestParams)
 	if err != nil {
 		return ErrorResponse{}, err
 	}
 
-	res, errResp, err := sendRequest(req)
+	res, errResp, err := sendRequest(client, req)
 	if err != nil {
 		return ErrorResponse{}, err
 	}
@@ -37,19 +38,21 @@ func GetRequest(returnValuePointer interface{}, baseUrl string, requestPath stri
 	return ErrorResponse{}, nil
 }
 
-// PutRequest makes the put JSON request and return the body
+// PutRequest makes the put JSON request with the client and return the body
 func PutRequest(
+	ctx context.Context,
+	client *http.Client,
 	returnValuePointer interface{},
 	baseUrl string, requestPath string,
 	requestParams url.Values,
 	data interface{}) (ErrorResponse, error) {
 
-	req, err := createRequestWithRawData(http.MethodPut, baseUrl, requestPath, requestParams, data)
+	req, err := createRequestWithRawData(ctx, http.MethodPut, baseUrl, requestPath, requestParams, data)
 	if err != nil {
 		return ErrorResponse{}, err
 	}
 
-	res, errResp, err := sendRequest(req)
+	res, errResp, err := sendRequest(client, req)
 	if err != nil {
 		return ErrorResponse{}, err
 	}
@@ -66,14 +69,14 @@ func PutRequest(
 	return ErrorResponse{}, nil
 }
 
-// DeleteRequest makes the delete request and return the body
-func DeleteRequest(returnValuePointer interface{}, baseUrl string, requestPath string, requestParams url.Values) (ErrorResponse, error) {
-	req, err := createRequest(http.MethodDelete, baseUrl, requestPath, requestParams)
+// DeleteRequest makes the delete request with the client and return the body
+func DeleteRequest(ctx context.Context, client *http.Client, returnValuePointer interface{}, baseUrl string, requestPath string, requestParams url.Values) (ErrorResponse, error) {
+	req, err := createRequest(ctx, http.MethodDelete, baseUrl, requestPath, requestParams)
 	if err != nil {
 		return ErrorResponse{}, err
 	}
 
-	res, errResp, err := sendRequest(req)
+	res, errResp, err := sendRequest(client, req)
 	if err != nil {
 		return ErrorResponse{}, err
 	}
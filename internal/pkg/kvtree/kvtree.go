@@ -0,0 +1,172 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kvtree
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/cast"
+
+	"github.com/edgexfoundry/go-mod-configuration/v3/pkg/types"
+)
+
+// KeyDelimiter separates the levels of a key path
+const KeyDelimiter = "/"
+
+// Flatten converts a nested configuration value into key/value pairs, where the key is the path of each leaf value
+// relative to path.
+func Flatten(path string, value any) map[string]string {
+	pairs := make(map[string]string)
+	flatten(path, value, pairs)
+	return pairs
+}
+
+func flatten(path string, value any, pairs map[string]string) {
+	pathPre := ""
+	if path != "" {
+		pathPre = path + KeyDelimiter
+	}
+
+	switch value := value.(type) {
+	case []any:
+		for index, item := range value {
+			flatten(pathPre+strconv.Itoa(index), item, pairs)
+		}
+	case map[string]any:
+		for key, item := range value {
+			flatten(pathPre+key, item, pairs)
+		}
+	case nil:
+		pairs[path] = ""
+	default:
+		pairs[path] = cast.ToString(value)
+	}
+}
+
+// Expand converts the key/value pairs under prefix into a nested map. Keys outside of prefix are ignored.
+func Expand(prefix string, pairs map[string]string) (map[string]any, error) {
+	raw := make(map[string]any)
+	for _, fullKey := range SortedKeys(pairs) {
+		key, ok := trimPrefix(fullKey, prefix)
+		if !ok || key == "" {
+			continue
+		}
+
+		// Determine what map we're writing the value to. We split by '/'
+		// to determine any sub-maps that need to be created.
+		m := raw
+		children := strings.Split(key, KeyDelimiter)
+		key = children[len(children)-1]
+		for _, child := range children[:len(children)-1] {
+			if m[child] == nil {
+				m[child] = make(map[string]any)
+			}
+
+			subm, ok := m[child].(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("child is both a data item and dir: %s", child)
+			}
+
+			m = subm
+		}
+
+		if _, isDir := m[key].(map[string]any); isDir {
+			return nil, fmt.Errorf("child is both a data item and dir: %s", key)
+		}
+		m[key] = pairs[fullKey]
+	}
+
+	return raw, nil
+}
+
+// Decode converts the key/value pairs under prefix to the target configuration data type
+func Decode(prefix string, pairs map[string]string, target any) error {
+	raw, err := Expand(prefix, pairs)
+	if err != nil {
+		return err
+	}
+
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Metadata:         nil,
+		WeaklyTypedInput: true,
+		Result:           target,
+	})
+	if err != nil {
+		return fmt.Errorf("decoding failed, err: %v", err)
+	}
+	if err := decoder.Decode(raw); err != nil {
+		return fmt.Errorf("decoding failed, err: %v", err)
+	}
+
+	return nil
+}
+
+// HasPrefix reports whether key is either equal to prefix or located below it in the tree
+func HasPrefix(key string, prefix string) bool {
+	_, ok := trimPrefix(key, prefix)
+	return ok
+}
+
+// Subtree returns the key/value pairs located at or below prefix
+func Subtree(prefix string, pairs map[string]string) map[string]string {
+	subtree := make(map[string]string)
+	for key, value := range pairs {
+		if HasPrefix(key, prefix) {
+			subtree[key] = value
+		}
+	}
+	return subtree
+}
+
+// SortedKeys returns the keys of pairs in lexical order
+func SortedKeys(pairs map[string]string) []string {
+	keys := make([]string, 0, len(pairs))
+	for key := range pairs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Diff returns the changes from the old to the new key/value pairs, ordered by key
+func Diff(old map[string]string, new map[string]string) []types.ChangeEvent {
+	var events []types.ChangeEvent
+	for key, newValue := range new {
+		oldValue, exists := old[key]
+		switch {
+		case !exists:
+			events = append(events, types.ChangeEvent{Key: key, NewValue: []byte(newValue), Type: types.ChangeAdded})
+		case oldValue != newValue:
+			events = append(events, types.ChangeEvent{Key: key, OldValue: []byte(oldValue), NewValue: []byte(newValue), Type: types.ChangeModified})
+		}
+	}
+	for key, oldValue := range old {
+		if _, exists := new[key]; !exists {
+			events = append(events, types.ChangeEvent{Key: key, OldValue: []byte(oldValue), Type: types.ChangeDeleted})
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].Key < events[j].Key })
+	return events
+}
+
+func trimPrefix(key string, prefix string) (string, bool) {
+	prefix = strings.TrimSuffix(prefix, KeyDelimiter)
+	if prefix == "" {
+		return key, true
+	}
+	if key == prefix {
+		return "", true
+	}
+	if strings.HasPrefix(key, prefix+KeyDelimiter) {
+		return key[len(prefix)+1:], true
+	}
+	return "", false
+}
@@ -0,0 +1,96 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kvtree
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-configuration/v3/pkg/types"
+)
+
+func TestFlattenAndExpand(t *testing.T) {
+	nested := map[string]any{
+		"Port":    8000,
+		"Enabled": true,
+		"Empty":   nil,
+		"Writable": map[string]any{
+			"LogLevel": "INFO",
+		},
+		"List": []any{"a", "b"},
+	}
+
+	expected := map[string]string{
+		"service/Port":              "8000",
+		"service/Enabled":           "true",
+		"service/Empty":             "",
+		"service/Writable/LogLevel": "INFO",
+		"service/List/0":            "a",
+		"service/List/1":            "b",
+	}
+
+	pairs := Flatten("service", nested)
+	require.Equal(t, expected, pairs)
+
+	pairs["other/Port"] = "9000"
+	actual, err := Expand("service", pairs)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"Port":     "8000",
+		"Enabled":  "true",
+		"Empty":    "",
+		"Writable": map[string]any{"LogLevel": "INFO"},
+		"List":     map[string]any{"0": "a", "1": "b"},
+	}, actual)
+}
+
+func TestExpandDataItemAndDir(t *testing.T) {
+	_, err := Expand("", map[string]string{"a": "1", "a/b": "2"})
+	require.Error(t, err)
+}
+
+func TestDecode(t *testing.T) {
+	type writable struct {
+		LogLevel string
+		Port     int
+	}
+
+	target := writable{LogLevel: "DEBUG"}
+	err := Decode("service/Writable", map[string]string{"service/Writable/Port": "8000"}, &target)
+	require.NoError(t, err)
+	assert.Equal(t, writable{LogLevel: "DEBUG", Port: 8000}, target)
+}
+
+func TestHasPrefix(t *testing.T) {
+	assert.True(t, HasPrefix("service/Writable", "service/Writable"))
+	assert.True(t, HasPrefix("service/Writable/LogLevel", "service/Writable/"))
+	assert.True(t, HasPrefix("service/Writable", ""))
+	assert.False(t, HasPrefix("service/WritableX", "service/Writable"))
+}
+
+func TestDiff(t *testing.T) {
+	old := map[string]string{
+		"Writable/LogLevel": "INFO",
+		"Writable/Timeout":  "5s",
+		"Port":              "8000",
+	}
+	updated := map[string]string{
+		"Writable/LogLevel": "DEBUG",
+		"Port":              "8000",
+		"Host":              "localhost",
+	}
+
+	expected := []types.ChangeEvent{
+		{Key: "Host", NewValue: []byte("localhost"), Type: types.ChangeAdded},
+		{Key: "Writable/LogLevel", OldValue: []byte("INFO"), NewValue: []byte("DEBUG"), Type: types.ChangeModified},
+		{Key: "Writable/Timeout", OldValue: []byte("5s"), Type: types.ChangeDeleted},
+	}
+
+	assert.Equal(t, expected, Diff(old, updated))
+	assert.Empty(t, Diff(updated, updated))
+}
@@ -0,0 +1,339 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/kvtree"
+	"github.com/edgexfoundry/go-mod-configuration/v3/internal/pkg/watch"
+	"github.com/edgexfoundry/go-mod-configuration/v3/pkg/types"
+
+	"github.com/edgexfoundry/go-mod-messaging/v3/messaging"
+)
+
+type memoryClient struct {
+	configBasePath string
+	lock           sync.RWMutex
+	pairs          map[string]string
+	watches        map[*keyWatch]struct{}
+	// watchMutex guards watchingDoneCtx, which is replaced once StopWatching has stopped all watches
+	watchMutex      sync.Mutex
+	watchingDoneCtx context.Context
+	watchingDone    context.CancelFunc
+	watchingWait    sync.WaitGroup
+}
+
+// keyWatch is a registered WatchForChanges for the key prefix
+type keyWatch struct {
+	prefix string
+	// changed is signalled when a key under the prefix has been put. It is buffered so that
+	// signals are coalesced while the watch is busy sending the previous update.
+	changed chan struct{}
+}
+
+// NewMemoryClient creates a new Memory Client which holds the configuration key tree in process.
+func NewMemoryClient(config types.ServiceConfig) *memoryClient {
+	client := memoryClient{
+		configBasePath: strings.Trim(config.BasePath, kvtree.KeyDelimiter),
+		pairs:          make(map[string]string),
+		watches:        make(map[*keyWatch]struct{}),
+	}
+
+	client.watchingDoneCtx, client.watchingDone = context.WithCancel(context.Background())
+
+	return &client
+}
+
+func (client *memoryClient) fullPath(name string) string {
+	return path.Join(client.configBasePath, name)
+}
+
+// IsAlive always returns true since the key tree is held in process
+func (client *memoryClient) IsAlive() bool {
+	return true
+}
+
+// HasConfiguration checks to see if the key tree contains the service's configuration.
+func (client *memoryClient) HasConfiguration() (bool, error) {
+	return client.hasKeys(client.configBasePath), nil
+}
+
+// HasSubConfiguration checks to see if the key tree contains the service's sub configuration.
+func (client *memoryClient) HasSubConfiguration(name string) (bool, error) {
+	return client.hasKeys(client.fullPath(name)), nil
+}
+
+func (client *memoryClient) hasKeys(prefix string) bool {
+	client.lock.RLock()
+	defer client.lock.RUnlock()
+
+	for key := range client.pairs {
+		if kvtree.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PutConfigurationMap puts a full configuration map into the key tree.
+// The sub-paths to where the values are to be stored are generated from the map key.
+func (client *memoryClient) PutConfigurationMap(configuration map[string]any, overwrite bool) error {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	var changedKeys []string
+	for key, value := range kvtree.Flatten("", configuration) {
+		keyPath := client.fullPath(key)
+		if _, exists := client.pairs[keyPath]; !exists || overwrite {
+			client.pairs[keyPath] = value
+			changedKeys = append(changedKeys, keyPath)
+		}
+	}
+
+	client.notifyWatches(changedKeys...)
+	return nil
+}
+
+// PutConfiguration puts a full configuration struct into the key tree
+func (client *memoryClient) PutConfiguration(configuration interface{}, overwrite bool) error {
+	configMap := make(map[string]any)
+	bytes, err := json.Marshal(configuration)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(bytes, &configMap)
+	if err != nil {
+		return err
+	}
+
+	return client.PutConfigurationMap(configMap, overwrite)
+}
+
+// GetConfiguration gets the full configuration from the key tree into the target configuration struct.
+// Returns the configuration in the target struct as interface{}, which caller must cast
+func (client *memoryClient) GetConfiguration(configStruct interface{}) (interface{}, error) {
+	subtree := client.subtree(client.configBasePath)
+	if len(subtree) == 0 {
+		return nil, fmt.Errorf("the Configuration service (memory) doesn't contain configuration for %s", client.configBasePath)
+	}
+
+	if err := kvtree.Decode(client.configBasePath, subtree, configStruct); err != nil {
+		return nil, err
+	}
+
+	return configStruct, nil
+}
+
+// WatchForChanges sends back updates on the update channel whenever a value under the target key is put.
+// The current configuration is sent once the watch has started.
+// Sends the configuration in the target struct as interface{} on updateChannel, which caller must cast
+func (client *memoryClient) WatchForChanges(updateChannel chan<- interface{}, errorChannel chan<- error, configuration interface{}, watchKey string, _ messaging.MessageClient) {
+	client.watchForChanges(context.Background(), updateChannel, errorChannel, configuration, watchKey, func(error) {})
+}
+
+// Watch sets up a watch for the target key like WatchForChanges and returns the handle to stop it,
+// independently of the other watches of the client. The watch also stops once ctx is done.
+func (client *memoryClient) Watch(ctx context.Context, updateChannel chan<- interface{}, errorChannel chan<- error, configuration interface{}, watchKey string, _ messaging.MessageClient) types.Watcher {
+	watcher, ctx := watch.New(ctx)
+	client.watchForChanges(ctx, updateChannel, errorChannel, configuration, watchKey, watcher.Finish)
+	return watcher
+}
+
+// watchForChanges sends the updates of the target key until either ctx is done or StopWatching is called,
+// and calls exited with the reason once it has stopped.
+func (client *memoryClient) watchForChanges(ctx context.Context, updateChannel chan<- interface{}, errorChannel chan<- error, configuration interface{}, watchKey string, exited func(error)) {
+	w := &keyWatch{
+		prefix:  client.fullPath(watchKey),
+		changed: make(chan struct{}, 1),
+	}
+
+	client.lock.Lock()
+	client.watches[w] = struct{}{}
+	client.lock.Unlock()
+
+	// signal the initial update
+	w.changed <- struct{}{}
+
+	ctx, watchDone := client.startWatch(ctx)
+	go func() {
+		defer func() {
+			client.lock.Lock()
+			delete(client.watches, w)
+			client.lock.Unlock()
+			exited(ctx.Err())
+			watchDone()
+		}()
+
+		var previous map[string]string
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-w.changed:
+			}
+
+			subtree := client.subtree(w.prefix)
+			if previous != nil && maps.Equal(previous, subtree) {
+				continue
+			}
+			previous = subtree
+
+			if err := kvtree.Decode(w.prefix, subtree, configuration); err != nil {
+				select {
+				case <-ctx.Done():
+					return
+				case errorChannel <- err:
+				}
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case updateChannel <- configuration:
+			}
+		}
+	}()
+}
+
+// StopWatching causes all WatchForChanges processing to stop and waits until they have exited.
+func (client *memoryClient) StopWatching() {
+	client.watchMutex.Lock()
+	defer client.watchMutex.Unlock()
+
+	client.watchingDone()
+	client.watchingWait.Wait()
+
+	client.watchingDoneCtx, client.watchingDone = context.WithCancel(context.Background())
+}
+
+// startWatch tracks a new watch and returns its context, which is done once either ctx is done or StopWatching is
+// called, along with the function to call once the watch has exited
+func (client *memoryClient) startWatch(ctx context.Context) (context.Context, func()) {
+	client.watchMutex.Lock()
+	defer client.watchMutex.Unlock()
+
+	client.watchingWait.Add(1)
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(client.watchingDoneCtx, cancel)
+
+	return ctx, func() {
+		stop()
+		cancel()
+		client.watchingWait.Done()
+	}
+}
+
+// ConfigurationValueExists checks if a configuration value exists in the key tree
+func (client *memoryClient) ConfigurationValueExists(name string) (bool, error) {
+	client.lock.RLock()
+	defer client.lock.RUnlock()
+
+	_, exists := client.pairs[client.fullPath(name)]
+	return exists, nil
+}
+
+// GetConfigurationValue gets a specific configuration value from the key tree
+func (client *memoryClient) GetConfigurationValue(name string) ([]byte, error) {
+	return client.GetConfigurationValueByFullPath(client.fullPath(name))
+}
+
+// GetConfigurationValueByFullPath gets a specific configuration value given the full path from the key tree
+func (client *memoryClient) GetConfigurationValueByFullPath(fullPath string) ([]byte, error) {
+	client.lock.RLock()
+	defer client.lock.RUnlock()
+
+	value, exists := client.pairs[strings.Trim(fullPath, kvtree.KeyDelimiter)]
+	if !exists {
+		return nil, nil
+	}
+
+	return []byte(value), nil
+}
+
+// PutConfigurationValue puts a specific configuration value into the key tree
+func (client *memoryClient) PutConfigurationValue(name string, value []byte) error {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	keyPath := client.fullPath(name)
+	client.pairs[keyPath] = string(value)
+	client.notifyWatches(keyPath)
+
+	return nil
+}
+
+// GetConfigurationKeys returns the full path of all keys under name
+func (client *memoryClient) GetConfigurationKeys(name string) ([]string, error) {
+	subtree := client.subtree(client.fullPath(name))
+	if len(subtree) == 0 {
+		return nil, nil
+	}
+
+	return kvtree.SortedKeys(subtree), nil
+}
+
+// DeleteConfigurationValue removes a specific configuration value from the key tree
+func (client *memoryClient) DeleteConfigurationValue(name string) error {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	keyPath := client.fullPath(name)
+	if _, exists := client.pairs[keyPath]; exists {
+		delete(client.pairs, keyPath)
+		client.notifyWatches(keyPath)
+	}
+
+	return nil
+}
+
+// DeleteSubConfiguration removes all configuration values located under name from the key tree
+func (client *memoryClient) DeleteSubConfiguration(name string) error {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	var deletedKeys []string
+	for key := range kvtree.Subtree(client.fullPath(name), client.pairs) {
+		delete(client.pairs, key)
+		deletedKeys = append(deletedKeys, key)
+	}
+	client.notifyWatches(deletedKeys...)
+
+	return nil
+}
+
+func (client *memoryClient) subtree(prefix string) map[string]string {
+	client.lock.RLock()
+	defer client.lock.RUnlock()
+
+	return kvtree.Subtree(prefix, client.pairs)
+}
+
+// notifyWatches signals the watches for which any of the keys are under their prefix.
+// Must be called with the lock held.
+func (client *memoryClient) notifyWatches(keys ...string) {
+	for w := range client.watches {
+		for _, key := range keys {
+			if kvtree.HasPrefix(key, w.prefix) {
+				select {
+				case w.changed <- struct{}{}:
+				default:
+					// a change is already pending
+				}
+				break
+			}
+		}
+	}
+}
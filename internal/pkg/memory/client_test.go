@@ -0,0 +1,232 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-configuration/v3/pkg/types"
+)
+
+const basePath = "edgex/v3/core-data"
+
+type LoggingInfo struct {
+	EnableRemote bool
+	File         string
+}
+
+type TestConfig struct {
+	Logging  LoggingInfo
+	Port     int
+	Host     string
+	LogLevel string
+}
+
+var expectedConfig = TestConfig{
+	Logging: LoggingInfo{
+		EnableRemote: true,
+		File:         "NONE",
+	},
+	Port:     8000,
+	Host:     "localhost",
+	LogLevel: "debug",
+}
+
+func makeMemoryClient() *memoryClient {
+	return NewMemoryClient(types.ServiceConfig{Type: "memory", BasePath: basePath})
+}
+
+func TestIsAlive(t *testing.T) {
+	assert.True(t, makeMemoryClient().IsAlive())
+}
+
+func TestGetConfiguration(t *testing.T) {
+	client := makeMemoryClient()
+
+	_, err := client.GetConfiguration(&TestConfig{})
+	require.Error(t, err)
+
+	require.NoError(t, client.PutConfiguration(expectedConfig, true))
+
+	exists, err := client.HasConfiguration()
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = client.HasSubConfiguration("Logging")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	result, err := client.GetConfiguration(&TestConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, expectedConfig, *result.(*TestConfig))
+}
+
+func TestPutConfigurationMap(t *testing.T) {
+	client := makeMemoryClient()
+
+	configMap := map[string]any{"nestedNode": map[string]any{"field1": "value1", "field2": "value2"}}
+	require.NoError(t, client.PutConfigurationMap(configMap, false))
+
+	configMap["nestedNode"] = map[string]any{"field1": "overwrite1"}
+	require.NoError(t, client.PutConfigurationMap(configMap, false))
+
+	actual, err := client.GetConfigurationValue("nestedNode/field1")
+	require.NoError(t, err)
+	assert.Equal(t, "value1", string(actual))
+
+	require.NoError(t, client.PutConfigurationMap(configMap, true))
+
+	actual, err = client.GetConfigurationValue("nestedNode/field1")
+	require.NoError(t, err)
+	assert.Equal(t, "overwrite1", string(actual))
+
+	keys, err := client.GetConfigurationKeys("nestedNode")
+	require.NoError(t, err)
+	assert.Equal(t, []string{basePath + "/nestedNode/field1", basePath + "/nestedNode/field2"}, keys)
+}
+
+func TestConfigurationValue(t *testing.T) {
+	client := makeMemoryClient()
+
+	exists, err := client.ConfigurationValueExists("Foo")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	require.NoError(t, client.PutConfigurationValue("Foo", []byte("bar")))
+
+	exists, err = client.ConfigurationValueExists("Foo")
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	actual, err := client.GetConfigurationValueByFullPath(basePath + "/Foo")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("bar"), actual)
+}
+
+func TestDelete(t *testing.T) {
+	client := makeMemoryClient()
+
+	require.NoError(t, client.PutConfigurationValue("Foo", []byte("bar")))
+	require.NoError(t, client.PutConfigurationValue("Writable/LogLevel", []byte("INFO")))
+	require.NoError(t, client.PutConfigurationValue("Writable/InsecureSecrets/DB/Path", []byte("redisdb")))
+	require.NoError(t, client.PutConfigurationValue("WritableExtra", []byte("true")))
+
+	require.NoError(t, client.DeleteConfigurationValue("Foo"))
+	require.NoError(t, client.DeleteConfigurationValue("Foo"))
+	require.NoError(t, client.DeleteSubConfiguration("Writable"))
+
+	keys, err := client.GetConfigurationKeys("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{basePath + "/WritableExtra"}, keys)
+}
+
+func TestWatchForChanges(t *testing.T) {
+	client := makeMemoryClient()
+	defer client.StopWatching()
+
+	require.NoError(t, client.PutConfiguration(expectedConfig, true))
+
+	updates := make(chan interface{})
+	errs := make(chan error)
+	client.WatchForChanges(updates, errs, &LoggingInfo{}, "Logging", nil)
+
+	initial := receiveUpdate(t, updates, errs)
+	assert.Equal(t, "NONE", initial.File)
+
+	// changes outside the watched key must not trigger an update
+	require.NoError(t, client.PutConfigurationValue("LogLevel", []byte("INFO")))
+	require.NoError(t, client.PutConfigurationValue("Logging/File", []byte("log.txt")))
+
+	updated := receiveUpdate(t, updates, errs)
+	assert.Equal(t, "log.txt", updated.File)
+	assert.True(t, updated.EnableRemote)
+}
+
+func TestStopWatching(t *testing.T) {
+	client := makeMemoryClient()
+
+	updates := make(chan interface{})
+	errs := make(chan error)
+	client.WatchForChanges(updates, errs, &LoggingInfo{}, "Logging", nil)
+	client.WatchForChanges(updates, errs, &TestConfig{}, "", nil)
+
+	stopped := make(chan struct{})
+	go func() {
+		client.StopWatching()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("StopWatching did not return")
+	}
+
+	// puts after the watches have stopped must not block
+	require.NoError(t, client.PutConfigurationValue("Logging/File", []byte("log.txt")))
+	assert.Empty(t, client.watches)
+}
+
+func TestWatchForChangesAfterStopWatching(t *testing.T) {
+	client := makeMemoryClient()
+	require.NoError(t, client.PutConfiguration(expectedConfig, true))
+
+	updates := make(chan interface{})
+	errs := make(chan error)
+	client.WatchForChanges(updates, errs, &LoggingInfo{}, "Logging", nil)
+	client.StopWatching()
+
+	client.WatchForChanges(updates, errs, &LoggingInfo{}, "Logging", nil)
+	defer client.StopWatching()
+
+	assert.Equal(t, "NONE", receiveUpdate(t, updates, errs).File)
+
+	require.NoError(t, client.PutConfigurationValue("Logging/File", []byte("log.txt")))
+	assert.Equal(t, "log.txt", receiveUpdate(t, updates, errs).File)
+}
+
+func TestWatch(t *testing.T) {
+	client := makeMemoryClient()
+	defer client.StopWatching()
+
+	require.NoError(t, client.PutConfiguration(expectedConfig, true))
+
+	stoppedUpdates := make(chan interface{})
+	updates := make(chan interface{})
+	errs := make(chan error)
+	stopped := client.Watch(context.Background(), stoppedUpdates, errs, &LoggingInfo{}, "Logging", nil)
+	running := client.Watch(context.Background(), updates, errs, &LoggingInfo{}, "Logging", nil)
+
+	// stopping a watch doesn't stop the others
+	stopped.Stop()
+	assert.ErrorIs(t, stopped.Err(), context.Canceled)
+	assert.Equal(t, "NONE", receiveUpdate(t, updates, errs).File)
+
+	select {
+	case <-running.Done():
+		t.Fatal("stopping a watch must not stop the other watches")
+	default:
+	}
+}
+
+func receiveUpdate(t *testing.T, updates chan interface{}, errs chan error) *LoggingInfo {
+	select {
+	case raw := <-updates:
+		actual, ok := raw.(*LoggingInfo)
+		require.True(t, ok, "unexpected update type")
+		return actual
+	case err := <-errs:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for update")
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-configuration/v3/pkg/types"
+)
+
+// Do calls the operation until it succeeds, fails with an error for which isRetryable returns false,
+// the maximum number of attempts of the policy is reached or ctx is done.
+// The error of the last attempt is returned as is if only one attempt was made, otherwise a *types.RetryError
+// holding the error of every attempt is returned.
+func Do(ctx context.Context, policy types.RetryInfo, isRetryable func(error) bool, operation func(ctx context.Context) error) error {
+	var attempts []error
+
+	maxAttempts := policy.GetMaxAttempts()
+	for attempt := 1; ; attempt++ {
+		err := operation(ctx)
+		if err == nil {
+			return nil
+		}
+
+		attempts = append(attempts, err)
+		if attempt >= maxAttempts || ctx.Err() != nil || !isRetryable(err) {
+			break
+		}
+
+		timer := time.NewTimer(Backoff(policy, attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			attempts = append(attempts, ctx.Err())
+		case <-timer.C:
+			continue
+		}
+		break
+	}
+
+	if len(attempts) == 1 {
+		return attempts[0]
+	}
+
+	return &types.RetryError{Attempts: attempts}
+}
+
+// Backoff returns the wait time after the given failed attempt, starting at 1
+func Backoff(policy types.RetryInfo, attempt int) time.Duration {
+	backoff := policy.GetInitialBackoff()
+	maxBackoff := policy.GetMaxBackoff()
+	for i := 1; i < attempt && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+
+	if jitter := min(max(policy.Jitter, 0), 1); jitter > 0 {
+		// vary the wait time randomly within +/- jitter
+		factor := 1 - jitter + rand.Float64()*2*jitter // nolint: gosec
+		backoff = time.Duration(float64(backoff) * factor)
+	}
+
+	return backoff
+}
+
+// IsNetworkError checks if the error is caused by the service not being reachable or not responding in time
+func IsNetworkError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// IsRetryableStatus checks if the HTTP status code is returned for transient failures
+func IsRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
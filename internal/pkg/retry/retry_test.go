@@ -0,0 +1,130 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-configuration/v3/pkg/types"
+)
+
+var errTransient = errors.New("transient")
+
+func isTransient(err error) bool {
+	return errors.Is(err, errTransient)
+}
+
+func TestDo(t *testing.T) {
+	policy := types.RetryInfo{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+	errPermanent := errors.New("permanent")
+
+	tests := []struct {
+		name             string
+		errs             []error
+		expectedAttempts int
+		expectedErr      error
+		expectRetryError bool
+	}{
+		{"Success", nil, 1, nil, false},
+		{"Success after retry", []error{errTransient}, 2, nil, false},
+		{"Permanent failure", []error{errPermanent}, 1, errPermanent, false},
+		{"Permanent failure after retry", []error{errTransient, errPermanent}, 2, errPermanent, true},
+		{"Attempts exhausted", []error{errTransient, errTransient, errTransient, nil}, 3, errTransient, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			attempts := 0
+			err := Do(context.Background(), policy, isTransient, func(ctx context.Context) error {
+				attempts++
+				if attempts > len(test.errs) {
+					return nil
+				}
+				return test.errs[attempts-1]
+			})
+
+			assert.Equal(t, test.expectedAttempts, attempts)
+			if test.expectedErr == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorIs(t, err, test.expectedErr)
+			var retryErr *types.RetryError
+			assert.Equal(t, test.expectRetryError, errors.As(err, &retryErr))
+			if test.expectRetryError {
+				assert.Len(t, retryErr.Attempts, test.expectedAttempts)
+			}
+		})
+	}
+}
+
+func TestDoNoRetryByDefault(t *testing.T) {
+	attempts := 0
+	err := Do(context.Background(), types.RetryInfo{}, isTransient, func(ctx context.Context) error {
+		attempts++
+		return errTransient
+	})
+
+	assert.Equal(t, 1, attempts)
+	assert.Equal(t, errTransient, err)
+}
+
+func TestDoContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	policy := types.RetryInfo{MaxAttempts: 10, InitialBackoff: time.Hour}
+
+	attempts := 0
+	err := Do(ctx, policy, isTransient, func(ctx context.Context) error {
+		attempts++
+		cancel()
+		return errTransient
+	})
+
+	assert.Equal(t, 1, attempts)
+	assert.ErrorIs(t, err, errTransient)
+}
+
+func TestBackoff(t *testing.T) {
+	policy := types.RetryInfo{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	assert.Equal(t, 100*time.Millisecond, Backoff(policy, 1))
+	assert.Equal(t, 200*time.Millisecond, Backoff(policy, 2))
+	assert.Equal(t, 800*time.Millisecond, Backoff(policy, 4))
+	assert.Equal(t, time.Second, Backoff(policy, 5))
+	assert.Equal(t, time.Second, Backoff(policy, 100))
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		actual := Backoff(policy, 1)
+		assert.GreaterOrEqual(t, actual, 50*time.Millisecond)
+		assert.LessOrEqual(t, actual, 150*time.Millisecond)
+	}
+}
+
+func TestIsRetryableStatus(t *testing.T) {
+	assert.True(t, IsRetryableStatus(http.StatusServiceUnavailable))
+	assert.True(t, IsRetryableStatus(http.StatusTooManyRequests))
+	assert.False(t, IsRetryableStatus(http.StatusNotImplemented))
+	assert.False(t, IsRetryableStatus(http.StatusForbidden))
+}
+
+func TestIsNetworkError(t *testing.T) {
+	client := http.Client{}
+	_, err := client.Get("http://localhost:1")
+	require.Error(t, err)
+
+	assert.True(t, IsNetworkError(err))
+	assert.True(t, IsNetworkError(context.DeadlineExceeded))
+	assert.False(t, IsNetworkError(errTransient))
+}
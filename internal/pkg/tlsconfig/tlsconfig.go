@@ -0,0 +1,112 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/edgexfoundry/go-mod-configuration/v3/pkg/types"
+)
+
+var versions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// New creates the TLS configuration of the connections to the Configuration service from info
+func New(info types.TLSInfo) (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName: info.ServerName,
+	}
+
+	if info.MinVersion != "" {
+		version, found := versions[info.MinVersion]
+		if !found {
+			return nil, fmt.Errorf("unsupported minimum TLS version '%s'", info.MinVersion)
+		}
+		config.MinVersion = version
+	}
+
+	if info.CAFile != "" || info.CAPEM != "" {
+		pool := x509.NewCertPool()
+		if info.CAFile != "" {
+			caPEM, err := os.ReadFile(info.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read CA file: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(caPEM) {
+				return nil, fmt.Errorf("no valid certificate found in CA file %s", info.CAFile)
+			}
+		}
+		if info.CAPEM != "" && !pool.AppendCertsFromPEM([]byte(info.CAPEM)) {
+			return nil, errors.New("no valid certificate found in CA PEM")
+		}
+		config.RootCAs = pool
+	}
+
+	certificate, err := clientCertificate(info)
+	if err != nil {
+		return nil, err
+	}
+	if certificate != nil {
+		config.Certificates = []tls.Certificate{*certificate}
+	}
+
+	return config, nil
+}
+
+func clientCertificate(info types.TLSInfo) (*tls.Certificate, error) {
+	certPEM := []byte(info.CertPEM)
+	if len(certPEM) == 0 && info.CertFile != "" {
+		var err error
+		if certPEM, err = os.ReadFile(info.CertFile); err != nil {
+			return nil, fmt.Errorf("unable to read client certificate file: %w", err)
+		}
+	}
+
+	keyPEM := []byte(info.KeyPEM)
+	if len(keyPEM) == 0 && info.KeyFile != "" {
+		var err error
+		if keyPEM, err = os.ReadFile(info.KeyFile); err != nil {
+			return nil, fmt.Errorf("unable to read client key file: %w", err)
+		}
+	}
+
+	if len(certPEM) == 0 && len(keyPEM) == 0 {
+		return nil, nil
+	}
+	if len(certPEM) == 0 || len(keyPEM) == 0 {
+		return nil, errors.New("both the client certificate and its key must be set for mutual TLS")
+	}
+
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client certificate: %w", err)
+	}
+
+	return &certificate, nil
+}
+
+// NewTransport creates the HTTP transport of the requests to the Configuration service, with the TLS configuration
+// from info applied
+func NewTransport(info types.TLSInfo) (*http.Transport, error) {
+	config, err := New(info)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = config
+
+	return transport, nil
+}
@@ -0,0 +1,160 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-configuration/v3/pkg/types"
+)
+
+// makeClientCertificate creates a self-signed client certificate and returns it and its key PEM encoded
+func makeClientCertificate(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "edgex-service"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return string(certPEM), string(keyPEM)
+}
+
+func serverCAPEM(server *httptest.Server) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+}
+
+func get(t *testing.T, info types.TLSInfo, url string) error {
+	transport, err := NewTransport(info)
+	require.NoError(t, err)
+
+	client := http.Client{Transport: transport, Timeout: 5 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	return nil
+}
+
+func TestNewTransport(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	caPEM := serverCAPEM(server)
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, []byte(caPEM), 0600))
+
+	tests := []struct {
+		name          string
+		info          types.TLSInfo
+		expectedError bool
+	}{
+		{"CA PEM", types.TLSInfo{CAPEM: caPEM}, false},
+		{"CA file", types.TLSInfo{CAFile: caFile}, false},
+		{"Server name", types.TLSInfo{CAPEM: caPEM, ServerName: "example.com"}, false},
+		{"Minimum version", types.TLSInfo{CAPEM: caPEM, MinVersion: "1.3"}, false},
+		{"Untrusted", types.TLSInfo{MinVersion: "1.2"}, true},
+		{"Server name mismatch", types.TLSInfo{CAPEM: caPEM, ServerName: "other.example"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := get(t, test.info, server.URL)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestNewTransportMutualTLS(t *testing.T) {
+	certPEM, keyPEM := makeClientCertificate(t)
+
+	clientCAs := x509.NewCertPool()
+	require.True(t, clientCAs.AppendCertsFromPEM([]byte(certPEM)))
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	caPEM := serverCAPEM(server)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, []byte(certPEM), 0600))
+	require.NoError(t, os.WriteFile(keyFile, []byte(keyPEM), 0600))
+
+	require.NoError(t, get(t, types.TLSInfo{CAPEM: caPEM, CertPEM: certPEM, KeyPEM: keyPEM}, server.URL))
+	require.NoError(t, get(t, types.TLSInfo{CAPEM: caPEM, CertFile: certFile, KeyFile: keyFile}, server.URL))
+	require.Error(t, get(t, types.TLSInfo{CAPEM: caPEM}, server.URL), "client certificate must be required")
+}
+
+func TestNewInvalid(t *testing.T) {
+	certPEM, keyPEM := makeClientCertificate(t)
+
+	tests := []struct {
+		name string
+		info types.TLSInfo
+	}{
+		{"Unsupported minimum version", types.TLSInfo{MinVersion: "2.0"}},
+		{"Missing CA file", types.TLSInfo{CAFile: filepath.Join(t.TempDir(), "missing.pem")}},
+		{"Invalid CA PEM", types.TLSInfo{CAPEM: "not a certificate"}},
+		{"Certificate without key", types.TLSInfo{CertPEM: certPEM}},
+		{"Key without certificate", types.TLSInfo{KeyPEM: keyPEM}},
+		{"Mismatched key", types.TLSInfo{CertPEM: certPEM, KeyPEM: "not a key"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := New(test.info)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,218 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package token
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-configuration/v3/pkg/types"
+)
+
+const (
+	// renewalFraction is the fraction of its lifetime after which an Access Token is renewed in the background
+	renewalFraction = 0.75
+	// minRenewalRetry is the minimum wait time before a failed background renewal is attempted again
+	minRenewalRetry = time.Second
+)
+
+// Provider holds the current Access Token, so that it can be swapped without recreating the clients using it.
+// Access Tokens with a known expiry are renewed in the background ahead of it, others once rejected.
+// A Provider is safe for concurrent use, simultaneous renewals are deduplicated into a single call of the callback.
+type Provider struct {
+	getToken types.GetAccessTokenWithTTLCallback
+	mutex    sync.Mutex
+	token    string
+	expiry   time.Time
+	timer    *time.Timer
+	stopped  bool
+	// renewing is the renewal in progress, if any
+	renewing *renewal
+}
+
+// renewal is a call of the callback, whose result is shared by all callers renewing the Access Token meanwhile
+type renewal struct {
+	done chan struct{}
+	err  error
+}
+
+// NewProvider creates the Provider of the Access Token set in config, renewed with its GetAccessTokenWithTTL or
+// GetAccessToken callback. The initial Access Token is retrieved with GetAccessTokenWithTTL if AccessToken isn't set.
+func NewProvider(config types.ServiceConfig) (*Provider, error) {
+	provider := &Provider{}
+
+	switch {
+	case config.GetAccessTokenWithTTL != nil:
+		provider.getToken = config.GetAccessTokenWithTTL
+	case config.GetAccessToken != nil:
+		provider.getToken = func() (string, time.Duration, error) {
+			token, err := config.GetAccessToken()
+			return token, 0, err
+		}
+	}
+
+	if config.AccessToken == "" && config.GetAccessTokenWithTTL != nil {
+		if err := provider.Renew(); err != nil {
+			return nil, err
+		}
+		return provider, nil
+	}
+
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	provider.set(config.AccessToken, 0)
+	return provider, nil
+}
+
+// Token returns the current Access Token
+func (p *Provider) Token() string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.token
+}
+
+// Expiry returns the time the current Access Token expires at, which is zero if unknown
+func (p *Provider) Expiry() time.Time {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.expiry
+}
+
+// CanRenew checks if the Access Token can be renewed, i.e. a callback has been set
+func (p *Provider) CanRenew() bool {
+	return p.getToken != nil
+}
+
+// Renew retrieves a new Access Token with the callback, which is used from then on. Callers renewing while a renewal
+// is in progress wait for its result rather than calling the callback again.
+func (p *Provider) Renew() error {
+	return p.renew(nil)
+}
+
+// RenewRejected renews the Access Token like Renew after a request made with token has been rejected, unless the
+// Access Token has already been renewed since, i.e. following the rejection of a simultaneous request.
+func (p *Provider) RenewRejected(token string) error {
+	return p.renew(&token)
+}
+
+func (p *Provider) renew(rejected *string) error {
+	if p.getToken == nil {
+		return errors.New("unable to renew access token: no callback set")
+	}
+
+	p.mutex.Lock()
+	if rejected != nil && *rejected != p.token {
+		p.mutex.Unlock()
+		return nil
+	}
+	if inProgress := p.renewing; inProgress != nil {
+		p.mutex.Unlock()
+		<-inProgress.done
+		return inProgress.err
+	}
+	current := &renewal{done: make(chan struct{})}
+	p.renewing = current
+	p.mutex.Unlock()
+
+	token, ttl, err := p.getToken()
+
+	p.mutex.Lock()
+	if err != nil {
+		current.err = fmt.Errorf("failed to renew access token: %w", err)
+	} else {
+		p.set(token, ttl)
+	}
+	p.renewing = nil
+	p.mutex.Unlock()
+
+	close(current.done)
+	return current.err
+}
+
+// Stop stops the background renewal of the Access Token
+func (p *Provider) Stop() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.stopped = true
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+}
+
+// set makes token the current Access Token, the mutex must be held
+func (p *Provider) set(token string, ttl time.Duration) {
+	p.token = token
+	p.expiry = expiry(token, ttl)
+
+	if p.getToken != nil && !p.expiry.IsZero() {
+		lifetime := time.Until(p.expiry)
+		p.schedule(time.Duration(float64(lifetime) * renewalFraction))
+	}
+}
+
+// schedule renews the Access Token in the background after delay, replacing any renewal already scheduled.
+// The mutex must be held.
+func (p *Provider) schedule(delay time.Duration) {
+	if p.stopped {
+		return
+	}
+
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+	p.timer = time.AfterFunc(max(delay, 0), p.renewInBackground)
+}
+
+func (p *Provider) renewInBackground() {
+	if err := p.Renew(); err == nil {
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	// retry while the current Access Token is still valid, afterward it is only renewed once rejected
+	remaining := time.Until(p.expiry)
+	if remaining > 0 {
+		p.schedule(max(remaining/4, minRenewalRetry))
+	}
+}
+
+// expiry returns the time the Access Token expires at, from either its time to live or the exp claim of a JSON Web
+// Token. It is zero if unknown.
+func expiry(token string, ttl time.Duration) time.Time {
+	if ttl > 0 {
+		return time.Now().Add(ttl)
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}
+	}
+
+	var claims struct {
+		Exp float64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp <= 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(int64(claims.Exp), 0)
+}
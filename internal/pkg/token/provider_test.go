@@ -0,0 +1,247 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package token
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-configuration/v3/pkg/types"
+)
+
+func makeJWT(exp time.Time) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"sub":"edgex","exp":%d}`, exp.Unix())))
+	return header + "." + payload + ".signature"
+}
+
+// makeCallback returns a callback which retrieves the Access Tokens token-1, token-2, ... each with the next time
+// to live of ttls, or an hour once they are used up, unless failing is set
+func makeCallback(calls *atomic.Int32, failing *atomic.Bool, ttls ...time.Duration) types.GetAccessTokenWithTTLCallback {
+	return func() (string, time.Duration, error) {
+		if failing != nil && failing.Load() {
+			return "", 0, errors.New("secret store unavailable")
+		}
+
+		call := calls.Add(1)
+		ttl := time.Hour
+		if int(call) <= len(ttls) {
+			ttl = ttls[call-1]
+		}
+		return fmt.Sprintf("token-%d", call), ttl, nil
+	}
+}
+
+func TestNewProviderStaticToken(t *testing.T) {
+	provider, err := NewProvider(types.ServiceConfig{AccessToken: "static"})
+	require.NoError(t, err)
+	defer provider.Stop()
+
+	assert.Equal(t, "static", provider.Token())
+	assert.True(t, provider.Expiry().IsZero())
+	assert.False(t, provider.CanRenew())
+	require.Error(t, provider.Renew())
+}
+
+func TestNewProviderJWT(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Truncate(time.Second)
+	token := makeJWT(exp)
+
+	provider, err := NewProvider(types.ServiceConfig{AccessToken: token, GetAccessToken: func() (string, error) {
+		return "renewed", nil
+	}})
+	require.NoError(t, err)
+	defer provider.Stop()
+
+	assert.Equal(t, token, provider.Token())
+	assert.True(t, exp.Equal(provider.Expiry()))
+	assert.True(t, provider.CanRenew())
+
+	require.NoError(t, provider.Renew())
+	assert.Equal(t, "renewed", provider.Token())
+	assert.True(t, provider.Expiry().IsZero())
+}
+
+func TestNewProviderInitialToken(t *testing.T) {
+	var calls atomic.Int32
+	provider, err := NewProvider(types.ServiceConfig{GetAccessTokenWithTTL: makeCallback(&calls, nil)})
+	require.NoError(t, err)
+	defer provider.Stop()
+
+	assert.Equal(t, "token-1", provider.Token())
+	assert.WithinDuration(t, time.Now().Add(time.Hour), provider.Expiry(), time.Minute)
+
+	var failing atomic.Bool
+	failing.Store(true)
+	_, err = NewProvider(types.ServiceConfig{GetAccessTokenWithTTL: makeCallback(&calls, &failing)})
+	require.Error(t, err)
+}
+
+func TestProviderRenewsAheadOfExpiry(t *testing.T) {
+	var calls atomic.Int32
+	ttl := 200 * time.Millisecond
+	provider, err := NewProvider(types.ServiceConfig{GetAccessTokenWithTTL: makeCallback(&calls, nil, ttl)})
+	require.NoError(t, err)
+	defer provider.Stop()
+
+	expiry := provider.Expiry()
+	require.Equal(t, "token-1", provider.Token())
+
+	require.Eventually(t, func() bool {
+		return provider.Token() == "token-2"
+	}, time.Second, 10*time.Millisecond)
+	assert.True(t, time.Now().Before(expiry), "token must be renewed before it expires")
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestProviderRetriesFailedRenewal(t *testing.T) {
+	var calls atomic.Int32
+	var failing atomic.Bool
+	provider, err := NewProvider(types.ServiceConfig{GetAccessTokenWithTTL: makeCallback(&calls, &failing, 2*time.Second)})
+	require.NoError(t, err)
+	defer provider.Stop()
+
+	// the renewal ahead of expiry fails and is attempted again later
+	failing.Store(true)
+	time.Sleep(1600 * time.Millisecond)
+	require.Equal(t, "token-1", provider.Token())
+	failing.Store(false)
+
+	require.Eventually(t, func() bool {
+		return provider.Token() == "token-2"
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestProviderStop(t *testing.T) {
+	var calls atomic.Int32
+	provider, err := NewProvider(types.ServiceConfig{GetAccessTokenWithTTL: makeCallback(&calls, nil, 50*time.Millisecond)})
+	require.NoError(t, err)
+
+	provider.Stop()
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, "token-1", provider.Token())
+}
+
+func TestTransport(t *testing.T) {
+	var received atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		received.Store(request.Header.Values("Authorization"))
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	for _, token := range []string{"secret", ""} {
+		provider, err := NewProvider(types.ServiceConfig{AccessToken: token})
+		require.NoError(t, err)
+
+		client := http.Client{Transport: &Transport{Provider: provider, Header: "Authorization", Prefix: "Bearer "}}
+		request, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+
+		resp, err := client.Do(request)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+
+		if token == "" {
+			assert.Empty(t, received.Load())
+		} else {
+			assert.Equal(t, []string{"Bearer " + token}, received.Load())
+		}
+		assert.Empty(t, request.Header.Get("Authorization"), "request must not be modified")
+	}
+}
+
+func TestProviderRenewDeduplicated(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	provider, err := NewProvider(types.ServiceConfig{
+		AccessToken: "token-0",
+		GetAccessToken: func() (string, error) {
+			<-release
+			return fmt.Sprintf("token-%d", calls.Add(1)), nil
+		},
+	})
+	require.NoError(t, err)
+	defer provider.Stop()
+
+	// all requests made with the same token are rejected at the same time
+	const rejections = 50
+	var started sync.WaitGroup
+	var done sync.WaitGroup
+	errs := make(chan error, rejections)
+	for i := 0; i < rejections; i++ {
+		started.Add(1)
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			started.Done()
+			errs <- provider.RenewRejected("token-0")
+		}()
+	}
+	started.Wait()
+	close(release)
+	done.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, "token-1", provider.Token())
+
+	// a request rejected with the renewed token renews it again
+	require.NoError(t, provider.RenewRejected("token-0"))
+	assert.Equal(t, int32(1), calls.Load())
+	require.NoError(t, provider.RenewRejected("token-1"))
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestProviderRenewFailureShared(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	provider, err := NewProvider(types.ServiceConfig{
+		AccessToken: "token-0",
+		GetAccessToken: func() (string, error) {
+			calls.Add(1)
+			<-release
+			return "", errors.New("secret store unavailable")
+		},
+	})
+	require.NoError(t, err)
+	defer provider.Stop()
+
+	var done sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			errs <- provider.Renew()
+		}()
+	}
+
+	require.Eventually(t, func() bool {
+		return calls.Load() == 1
+	}, time.Second, time.Millisecond)
+	close(release)
+	done.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.Error(t, err)
+	}
+	assert.Equal(t, "token-0", provider.Token())
+}
@@ -0,0 +1,38 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package token
+
+import (
+	"net/http"
+)
+
+// Transport sets the header of each request to the current Access Token of the Provider, unless it is empty
+type Transport struct {
+	Base     http.RoundTripper
+	Provider *Provider
+	// Header is the name of the header holding the Access Token
+	Header string
+	// Prefix precedes the Access Token in the header, i.e. "Bearer "
+	Prefix string
+}
+
+// RoundTrip makes the request with the Access Token header set
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	token := t.Provider.Token()
+	if token == "" {
+		return base.RoundTrip(req)
+	}
+
+	// a RoundTripper must not modify the request
+	req = req.Clone(req.Context())
+	req.Header.Set(t.Header, t.Prefix+token)
+	return base.RoundTrip(req)
+}
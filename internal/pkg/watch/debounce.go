@@ -0,0 +1,79 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package watch
+
+import (
+	"time"
+)
+
+// maxDelayFactor bounds how long a continuous stream of changes can hold back an update, in debounce windows
+const maxDelayFactor = 10
+
+// MaxDelay returns how long the update of a change is held back at most when debouncing with window
+func MaxDelay(window time.Duration) time.Duration {
+	return maxDelayFactor * window
+}
+
+// Debouncer coalesces the changes seen within a debounce window into a single update. The update is due once
+// no change has been seen for the window, or at most MaxDelay after the first change not yet sent.
+// A Debouncer isn't safe for concurrent use.
+type Debouncer struct {
+	window   time.Duration
+	timer    *time.Timer
+	pending  bool
+	deadline time.Time
+}
+
+// NewDebouncer returns a Debouncer coalescing the changes seen within window. Updates are due immediately if window
+// isn't positive.
+func NewDebouncer(window time.Duration) *Debouncer {
+	return &Debouncer{window: window}
+}
+
+// Changed records a change and reports whether its update is due immediately, i.e. without debouncing.
+// Otherwise, the update is due once C fires.
+func (d *Debouncer) Changed() bool {
+	if d.window <= 0 {
+		return true
+	}
+
+	now := time.Now()
+	if !d.pending {
+		d.pending = true
+		d.deadline = now.Add(MaxDelay(d.window))
+	}
+
+	wait := min(d.window, d.deadline.Sub(now))
+	if d.timer == nil {
+		d.timer = time.NewTimer(wait)
+	} else {
+		d.timer.Reset(wait)
+	}
+
+	return false
+}
+
+// C returns the channel which fires once the pending update is due, or nil if there is none.
+// Done must be called once it has fired.
+func (d *Debouncer) C() <-chan time.Time {
+	if !d.pending {
+		return nil
+	}
+
+	return d.timer.C
+}
+
+// Done marks the pending update as sent
+func (d *Debouncer) Done() {
+	d.pending = false
+}
+
+// Stop releases the timer of the Debouncer
+func (d *Debouncer) Stop() {
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+}
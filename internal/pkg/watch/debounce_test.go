@@ -0,0 +1,80 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package watch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebouncerWithoutWindow(t *testing.T) {
+	debouncer := NewDebouncer(0)
+	defer debouncer.Stop()
+
+	assert.True(t, debouncer.Changed())
+	assert.Nil(t, debouncer.C())
+}
+
+func TestDebouncerCoalesces(t *testing.T) {
+	window := 50 * time.Millisecond
+	debouncer := NewDebouncer(window)
+	defer debouncer.Stop()
+
+	assert.Nil(t, debouncer.C())
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		require.False(t, debouncer.Changed())
+		time.Sleep(10 * time.Millisecond)
+	}
+	lastChange := time.Now()
+
+	select {
+	case <-debouncer.C():
+		debouncer.Done()
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting on update to be due")
+	}
+	assert.GreaterOrEqual(t, time.Since(lastChange), window-10*time.Millisecond)
+	assert.GreaterOrEqual(t, time.Since(start), window+20*time.Millisecond)
+	assert.Nil(t, debouncer.C())
+
+	// the next change starts a new window
+	require.False(t, debouncer.Changed())
+	select {
+	case <-debouncer.C():
+		debouncer.Done()
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting on update to be due")
+	}
+}
+
+func TestDebouncerMaxDelay(t *testing.T) {
+	window := 10 * time.Millisecond
+	debouncer := NewDebouncer(window)
+	defer debouncer.Stop()
+
+	start := time.Now()
+	ticker := time.NewTicker(window / 2)
+	defer ticker.Stop()
+
+	// the changes never quiesce, so the update is due after the max delay
+	require.False(t, debouncer.Changed())
+	for {
+		select {
+		case <-debouncer.C():
+			assert.GreaterOrEqual(t, time.Since(start), MaxDelay(window))
+			return
+		case <-ticker.C:
+			debouncer.Changed()
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting on update to be due")
+		}
+	}
+}
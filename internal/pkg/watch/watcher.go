@@ -0,0 +1,59 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package watch
+
+import (
+	"context"
+	"sync"
+)
+
+// Watcher is the handle of a single watch, implementing types.Watcher
+type Watcher struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+	err    error
+}
+
+// New creates the handle of a watch which runs until either ctx is done or the handle is stopped.
+// The watch must run with the returned context and call Finish once it has exited.
+func New(ctx context.Context) (*Watcher, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Watcher{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}, ctx
+}
+
+// Finish marks the watch as exited because of err. Only the first call has any effect.
+func (w *Watcher) Finish(err error) {
+	w.once.Do(func() {
+		w.err = err
+		w.cancel()
+		close(w.done)
+	})
+}
+
+// Stop stops the watch and waits until it has exited
+func (w *Watcher) Stop() {
+	w.cancel()
+	<-w.done
+}
+
+// Done returns a channel which is closed once the watch has exited
+func (w *Watcher) Done() <-chan struct{} {
+	return w.done
+}
+
+// Err returns nil while the watch is running, and the reason why it has exited afterwards
+func (w *Watcher) Err() error {
+	select {
+	case <-w.done:
+		return w.err
+	default:
+		return nil
+	}
+}
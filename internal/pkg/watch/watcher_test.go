@@ -0,0 +1,66 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package watch
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/go-mod-configuration/v3/pkg/types"
+)
+
+var _ types.Watcher = &Watcher{}
+
+func TestStop(t *testing.T) {
+	watcher, ctx := New(context.Background())
+	go func() {
+		<-ctx.Done()
+		watcher.Finish(ctx.Err())
+	}()
+
+	assert.NoError(t, watcher.Err())
+	select {
+	case <-watcher.Done():
+		t.Fatal("watch exited before being stopped")
+	default:
+	}
+
+	watcher.Stop()
+	<-watcher.Done()
+	assert.ErrorIs(t, watcher.Err(), context.Canceled)
+
+	// Stopping again is a no-op
+	watcher.Stop()
+}
+
+func TestParentContextDone(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	watcher, ctx := New(parent)
+	go func() {
+		<-ctx.Done()
+		watcher.Finish(ctx.Err())
+	}()
+
+	cancel()
+	<-watcher.Done()
+	assert.ErrorIs(t, watcher.Err(), context.Canceled)
+}
+
+func TestFinishWithError(t *testing.T) {
+	expected := errors.New("subscribe failed")
+	watcher, ctx := New(context.Background())
+
+	watcher.Finish(expected)
+	watcher.Finish(context.Canceled)
+
+	require.Error(t, ctx.Err())
+	assert.Equal(t, expected, watcher.Err())
+	watcher.Stop()
+}
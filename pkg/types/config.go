@@ -21,12 +21,23 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
-const DefaultProtocol = "http"
+const (
+	DefaultProtocol       = "http"
+	DefaultTimeout        = 10 * time.Second
+	DefaultLoadTimeout    = 2 * time.Second
+	DefaultInitialBackoff = 500 * time.Millisecond
+	DefaultMaxBackoff     = 10 * time.Second
+)
 
 type GetAccessTokenCallback func() (string, error)
 
+// GetAccessTokenWithTTLCallback is a callback function that retrieves a new Access Token along with its time to live.
+// A time to live that isn't positive means the expiry of the Access Token is unknown.
+type GetAccessTokenWithTTLCallback func() (string, time.Duration, error)
+
 // ServiceConfig defines the information need to connect to the Configuration service and optionally register the service
 // for discovery and health checks
 type ServiceConfig struct {
@@ -43,13 +54,142 @@ type ServiceConfig struct {
 	// AccessToken is the token that is used to access the service configuration
 	AccessToken string
 	// GetAccessToken is a callback function that retrieves a new Access Token.
-	// This callback is used when a '403 Forbidden' status is received from any call to the configuration provider service.
+	// This callback is used when a '403 Forbidden' status, or '401 Unauthorized' for keeper, is received from any call to
+	// the configuration provider service. The call is then made again once with the new Access Token.
 	GetAccessToken GetAccessTokenCallback
+	// GetAccessTokenWithTTL is a callback function that retrieves a new Access Token along with its time to live, used
+	// instead of GetAccessToken if set. It retrieves the initial Access Token if AccessToken is not set.
+	// Access Tokens are renewed in the background ahead of their expiry, which is either their time to live or the
+	// exp claim of JSON Web Tokens.
+	GetAccessTokenWithTTL GetAccessTokenWithTTLCallback
+	// Path is the location of the configuration store used by local providers, i.e. file.
+	// It is either a directory without extension, in which each key is stored as a file, or a single JSON, YAML or
+	// TOML document.
+	Path string
+	// PollInterval is the interval at which providers that poll for changes check the watched configuration.
+	// A provider specific default is used if not set.
+	PollInterval time.Duration
+	// DebounceWindow is the period within which the changes to a watched configuration are coalesced into a single
+	// update, which is sent once no change has been seen for the period. A provider specific default is used if not set.
+	DebounceWindow time.Duration
+	// Timeouts are the timeouts of the operations made to the Configuration service
+	Timeouts TimeoutInfo
+	// Retry is the policy for retrying requests to the Configuration service after transient failures
+	Retry RetryInfo
+	// TLS is the TLS configuration used to connect to the Configuration service when Protocol is https
+	TLS TLSInfo
 	// Optional contains all other properties of the configuration provider might use.
 	// For example, it might need the message bus connection information to publish the config changes.
+	// The keeper provider connects to the message bus set in the "MessageBus" property to watch for changes.
 	Optional map[string]any
 }
 
+// TimeoutInfo defines the timeouts of the operations made to the Configuration service.
+// DefaultTimeout is used for any timeout that is not set, except Load.
+type TimeoutInfo struct {
+	// Load is the maximum time to load the full configuration. DefaultLoadTimeout is used if not set.
+	Load time.Duration
+	// Read is the maximum time of each request reading from the Configuration service
+	Read time.Duration
+	// Write is the maximum time of each request writing to the Configuration service
+	Write time.Duration
+	// Liveness is the maximum time to check if the Configuration service is up and running
+	Liveness time.Duration
+}
+
+func (info TimeoutInfo) GetLoad() time.Duration {
+	if info.Load <= 0 {
+		return DefaultLoadTimeout
+	}
+
+	return info.Load
+}
+
+func (info TimeoutInfo) GetRead() time.Duration {
+	return timeoutOrDefault(info.Read)
+}
+
+func (info TimeoutInfo) GetWrite() time.Duration {
+	return timeoutOrDefault(info.Write)
+}
+
+func (info TimeoutInfo) GetLiveness() time.Duration {
+	return timeoutOrDefault(info.Liveness)
+}
+
+func timeoutOrDefault(timeout time.Duration) time.Duration {
+	if timeout <= 0 {
+		return DefaultTimeout
+	}
+
+	return timeout
+}
+
+// RetryInfo defines how idempotent requests to the Configuration service are retried after transient failures,
+// such as the service not being reachable or responding with a 5xx status code.
+// Requests are only attempted once unless MaxAttempts is greater than 1.
+type RetryInfo struct {
+	// MaxAttempts is the maximum number of attempts made for each request, including the first one
+	MaxAttempts int
+	// InitialBackoff is the wait time before the first retry, which is doubled for each further retry.
+	// DefaultInitialBackoff is used if not set.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait time between two attempts. DefaultMaxBackoff is used if not set.
+	MaxBackoff time.Duration
+	// Jitter is the fraction, between 0 and 1, by which each wait time is randomly varied
+	Jitter float64
+}
+
+func (info RetryInfo) GetMaxAttempts() int {
+	if info.MaxAttempts < 1 {
+		return 1
+	}
+
+	return info.MaxAttempts
+}
+
+func (info RetryInfo) GetInitialBackoff() time.Duration {
+	if info.InitialBackoff <= 0 {
+		return DefaultInitialBackoff
+	}
+
+	return info.InitialBackoff
+}
+
+func (info RetryInfo) GetMaxBackoff() time.Duration {
+	if info.MaxBackoff <= 0 {
+		return DefaultMaxBackoff
+	}
+
+	return info.MaxBackoff
+}
+
+// TLSInfo defines the TLS settings used to connect to the Configuration service, including the client certificate
+// for mutual TLS. The system's CA bundle is used to verify the Configuration service if no CA is set.
+type TLSInfo struct {
+	// CAFile is the path of the PEM encoded CA bundle used to verify the Configuration service's certificate
+	CAFile string
+	// CAPEM is a PEM encoded CA bundle, which is used in addition to CAFile
+	CAPEM string
+	// CertFile is the path of the PEM encoded client certificate presented to the Configuration service
+	CertFile string
+	// KeyFile is the path of the PEM encoded private key of the client certificate
+	KeyFile string
+	// CertPEM is the PEM encoded client certificate, which is used instead of CertFile
+	CertPEM string
+	// KeyPEM is the PEM encoded private key of the client certificate, which is used instead of KeyFile
+	KeyPEM string
+	// ServerName is the host name the Configuration service's certificate is verified against, if not its host
+	ServerName string
+	// MinVersion is the minimum TLS version accepted, i.e. "1.2" or "1.3". The Go default is used if not set.
+	MinVersion string
+}
+
+// IsEnabled checks if any TLS setting is set
+func (info TLSInfo) IsEnabled() bool {
+	return info != TLSInfo{}
+}
+
 //
 // A few helper functions for building URLs.
 //
@@ -72,6 +212,13 @@ func (config *ServiceConfig) PopulateFromUrl(providerUrl string) error {
 		return fmt.Errorf("the format of Provider URL is incorrect (%s): %s", providerUrl, err.Error())
 	}
 
+	// Local providers, i.e. file:///etc/edgex/config.yaml, have a path rather than a host and port
+	if url.Host == "" && url.Path != "" {
+		config.Type = url.Scheme
+		config.Path = url.Path
+		return nil
+	}
+
 	port, err := strconv.Atoi(url.Port())
 	if err != nil {
 		return fmt.Errorf("the port from Provider URL is incorrect (%s): %s", providerUrl, err.Error())
@@ -97,3 +244,14 @@ func (config *ServiceConfig) PopulateFromUrl(providerUrl string) error {
 
 	return nil
 }
+
+// PutResult reports the outcome of writing a full configuration for each key, where the keys are the paths of the
+// configuration values relative to the service's base path.
+type PutResult struct {
+	// Written lists the keys which have been stored in the Configuration service
+	Written []string
+	// Skipped lists the keys which already existed and were not overwritten
+	Skipped []string
+	// Failed lists the keys which could not be stored in the Configuration service
+	Failed []string
+}
@@ -18,6 +18,7 @@ package types
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -74,6 +75,7 @@ func TestPopulateFromUrl(t *testing.T) {
 		ExpectedProtocol string
 		ExpectedHost     string
 		ExpectedPort     int
+		ExpectedPath     string
 		ExpectedError    string
 	}{
 		{
@@ -92,6 +94,12 @@ func TestPopulateFromUrl(t *testing.T) {
 			ExpectedHost:     "localhost",
 			ExpectedPort:     8080,
 		},
+		{
+			Name:         "Success, local path",
+			Url:          "file:///etc/edgex/configuration.yaml",
+			ExpectedType: "file",
+			ExpectedPath: "/etc/edgex/configuration.yaml",
+		},
 		{
 			Name:          "Bad URL format",
 			Url:           "not a url\r\n",
@@ -129,6 +137,37 @@ func TestPopulateFromUrl(t *testing.T) {
 			assert.Equal(t, test.ExpectedProtocol, target.Protocol)
 			assert.Equal(t, test.ExpectedHost, target.Host)
 			assert.Equal(t, test.ExpectedPort, target.Port)
+			assert.Equal(t, test.ExpectedPath, target.Path)
 		})
 	}
 }
+
+func TestTimeouts(t *testing.T) {
+	target := TimeoutInfo{
+		Load: 30 * time.Second,
+		Read: time.Second,
+	}
+
+	assert.Equal(t, 30*time.Second, target.GetLoad())
+	assert.Equal(t, time.Second, target.GetRead())
+	assert.Equal(t, DefaultTimeout, target.GetWrite())
+	assert.Equal(t, DefaultTimeout, target.GetLiveness())
+
+	// the full configuration is loaded within a shorter time by default
+	assert.Equal(t, DefaultLoadTimeout, TimeoutInfo{}.GetLoad())
+}
+
+func TestRetry(t *testing.T) {
+	target := RetryInfo{
+		InitialBackoff: time.Second,
+	}
+
+	assert.Equal(t, 1, target.GetMaxAttempts())
+	assert.Equal(t, time.Second, target.GetInitialBackoff())
+	assert.Equal(t, DefaultMaxBackoff, target.GetMaxBackoff())
+
+	target = RetryInfo{MaxAttempts: 5, MaxBackoff: time.Minute}
+	assert.Equal(t, 5, target.GetMaxAttempts())
+	assert.Equal(t, DefaultInitialBackoff, target.GetInitialBackoff())
+	assert.Equal(t, time.Minute, target.GetMaxBackoff())
+}
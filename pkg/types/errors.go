@@ -0,0 +1,73 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+)
+
+// RetryError is returned when a request to the Configuration service still failed after being retried.
+// It holds the error of every attempt, in order, so errors.Is and errors.As match against any of them.
+type RetryError struct {
+	Attempts []error
+}
+
+func (e *RetryError) Error() string {
+	if len(e.Attempts) == 0 {
+		return "request failed without any attempt"
+	}
+
+	return fmt.Sprintf("request failed after %d attempts, last error: %v", len(e.Attempts), e.Attempts[len(e.Attempts)-1])
+}
+
+func (e *RetryError) Unwrap() []error {
+	return e.Attempts
+}
+
+// ConflictError is returned by a compare-and-swap write when the version of the configuration value
+// in the Configuration service is no longer the expected one, i.e. the value has been changed in the meantime.
+type ConflictError struct {
+	Key             string
+	ExpectedVersion uint64
+	ActualVersion   uint64
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("configuration value %s has been changed, expected version %d but found version %d", e.Key, e.ExpectedVersion, e.ActualVersion)
+}
+
+// DegradedError is sent on the error channel of a watch when its message bus has reported an error, which might be
+// the loss of the subscription to the changes. The watch subscribes again, with backoff, if it has created the message
+// bus itself, and then re-reads the watched configuration so that no change is missed. It wraps the error reported by
+// the message bus.
+type DegradedError struct {
+	Key string
+	Err error
+}
+
+func (e *DegradedError) Error() string {
+	return fmt.Sprintf("watch of %s is degraded until its configuration is read again: %v", e.Key, e.Err)
+}
+
+func (e *DegradedError) Unwrap() error {
+	return e.Err
+}
+
+// AuthError is returned when the Configuration service has rejected a request as unauthorized, even after the
+// Access Token has been renewed with GetAccessToken, if set. It wraps the error of the rejected request or of the
+// failed renewal.
+type AuthError struct {
+	StatusCode int
+	Err        error
+}
+
+func (e *AuthError) Error() string {
+	return fmt.Sprintf("request rejected with status %d: %v", e.StatusCode, e.Err)
+}
+
+func (e *AuthError) Unwrap() error {
+	return e.Err
+}
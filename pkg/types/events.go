@@ -0,0 +1,29 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// ChangeType is the kind of change made to a configuration value
+type ChangeType string
+
+const (
+	ChangeAdded    ChangeType = "added"
+	ChangeModified ChangeType = "modified"
+	ChangeDeleted  ChangeType = "deleted"
+)
+
+// ChangeEvent describes the change of a single configuration value observed by a watch
+type ChangeEvent struct {
+	// Key is the path of the configuration value relative to the service's base path, i.e. Writable/LogLevel
+	Key string
+	// OldValue is the value before the change, nil if the value has been added
+	OldValue []byte
+	// NewValue is the value after the change, nil if the value has been deleted
+	NewValue []byte
+	// Type is the kind of change
+	Type ChangeType
+	// Index is the index of the Configuration service at which the change has been observed, if it has any
+	Index uint64
+}
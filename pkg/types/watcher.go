@@ -0,0 +1,17 @@
+//
+// Copyright (C) 2024 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// Watcher is the handle of a single watch set up on the Configuration service
+type Watcher interface {
+	// Stop stops the watch and waits until it has exited. Other watches of the same Client are not affected.
+	Stop()
+	// Done returns a channel which is closed once the watch has exited
+	Done() <-chan struct{}
+	// Err returns nil while the watch is running. Once Done is closed, it returns the reason why the watch has
+	// exited, i.e. context.Canceled after Stop or the error which prevented the watch from being set up.
+	Err() error
+}